@@ -0,0 +1,299 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"math/cmplx"
+	"os"
+	"slices"
+)
+
+// mean returns the arithmetic mean of x (0 for an empty slice).
+func mean(x []float64) float64 {
+	if len(x) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range x {
+		sum += v
+	}
+	return sum / float64(len(x))
+}
+
+// autocorrelation returns the normalised autocorrelation of x for lags
+// 0..maxLag. The series is mean-centred first; a constant series has no
+// correlation structure and gives nil.
+func autocorrelation(x []float64, maxLag int) []float64 {
+	n := len(x)
+	maxLag = min(maxLag, n-1)
+	if maxLag < 1 {
+		return nil
+	}
+
+	m := mean(x)
+	var0 := 0.0
+	for _, v := range x {
+		var0 += (v - m) * (v - m)
+	}
+	if var0 == 0 {
+		return nil
+	}
+
+	acf := make([]float64, maxLag+1)
+	for lag := 0; lag <= maxLag; lag++ {
+		sum := 0.0
+		for i := 0; i+lag < n; i++ {
+			sum += (x[i] - m) * (x[i+lag] - m)
+		}
+		acf[lag] = sum / var0
+	}
+	return acf
+}
+
+// dominantPeriod estimates the period of the main oscillation in x, in
+// samples, from its autocorrelation: it is the lag of the highest peak
+// after the first zero crossing. It returns 0 if the series does not
+// oscillate (no peak above 0.1), e.g. after an extinction.
+func dominantPeriod(x []float64) int {
+	acf := autocorrelation(x, len(x)/2)
+	if acf == nil {
+		return 0
+	}
+
+	lag := 1
+	for lag < len(acf) && acf[lag] > 0 {
+		lag++
+	}
+
+	best, bestLag := 0.1, 0
+	for ; lag < len(acf)-1; lag++ {
+		if acf[lag] > acf[lag-1] && acf[lag] >= acf[lag+1] && acf[lag] > best {
+			best, bestLag = acf[lag], lag
+		}
+	}
+	return bestLag
+}
+
+// fftPeriod estimates the period of the main oscillation in x, in samples,
+// from the highest peak of its power spectrum (ignoring the constant
+// term). The mean-centred series is zero-padded to a power of two for a
+// radix-2 FFT. It returns 0 for a constant series.
+func fftPeriod(x []float64) float64 {
+	n := 1
+	for n < len(x) {
+		n <<= 1
+	}
+	if n < 4 {
+		return 0
+	}
+
+	m := mean(x)
+	buf := make([]complex128, n)
+	for i, v := range x {
+		buf[i] = complex(v-m, 0)
+	}
+	fft(buf)
+
+	best, bestK := 0.0, 0
+	for k := 1; k <= n/2; k++ {
+		if p := real(buf[k])*real(buf[k]) + imag(buf[k])*imag(buf[k]); p > best {
+			best, bestK = p, k
+		}
+	}
+	if bestK == 0 {
+		return 0
+	}
+	return float64(n) / float64(bestK)
+}
+
+// fft computes the discrete Fourier transform of a in place. len(a) must
+// be a power of two.
+func fft(a []complex128) {
+	n := len(a)
+
+	// Bit-reversal permutation.
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			a[i], a[j] = a[j], a[i]
+		}
+	}
+
+	for size := 2; size <= n; size <<= 1 {
+		step := cmplx.Exp(complex(0, -2*math.Pi/float64(size)))
+		for start := 0; start < n; start += size {
+			w := complex(1, 0)
+			for k := 0; k < size/2; k++ {
+				u, v := a[start+k], w*a[start+k+size/2]
+				a[start+k], a[start+k+size/2] = u+v, u-v
+				w *= step
+			}
+		}
+	}
+}
+
+// amplitude returns half the range between the 5th and 95th percentiles of
+// x, a robust measure of how far the population swings around its mean.
+func amplitude(x []float64) float64 {
+	sorted := slices.Clone(x)
+	slices.Sort(sorted)
+	return (percentile(sorted, 95) - percentile(sorted, 5)) / 2
+}
+
+// peakLag returns the lag (0 <= lag < maxLag) at which the cross-correlation
+// of x with y shifted later in time is highest, i.e. how many steps peaks
+// of y tend to follow peaks of x.
+func peakLag(x, y []float64, maxLag int) int {
+	mx, my := mean(x), mean(y)
+	best, bestLag := math.Inf(-1), 0
+	for lag := 0; lag < maxLag && lag < len(x); lag++ {
+		sum := 0.0
+		for i := 0; i+lag < len(x); i++ {
+			sum += (x[i] - mx) * (y[i+lag] - my)
+		}
+		sum /= float64(len(x) - lag)
+		if sum > best {
+			best, bestLag = sum, lag
+		}
+	}
+	return bestLag
+}
+
+// LotkaVolterra holds the parameters of the model
+//
+//	dF/dt = A*F - B*F*S
+//	dS/dt = D*F*S - C*S
+//
+// fitted to a population series, with the R² of each equation.
+type LotkaVolterra struct {
+	A, B, C, D       float64
+	FishR2, SharksR2 float64
+}
+
+// fitLotkaVolterra fits the Lotka–Volterra equations to the one-step
+// population changes by linear least squares: ΔF is regressed on F and
+// -F·S, and ΔS on F·S and -S.
+func fitLotkaVolterra(fish, sharks []float64) LotkaVolterra {
+	n := len(fish) - 1
+	dF, dS := make([]float64, n), make([]float64, n)
+	f1, f2 := make([]float64, n), make([]float64, n)
+	s1, s2 := make([]float64, n), make([]float64, n)
+	for t := 0; t < n; t++ {
+		F, S := fish[t], sharks[t]
+		dF[t], dS[t] = fish[t+1]-F, sharks[t+1]-S
+		f1[t], f2[t] = F, -F*S
+		s1[t], s2[t] = F*S, -S
+	}
+
+	var lv LotkaVolterra
+	lv.A, lv.B, lv.FishR2 = leastSquares2(dF, f1, f2)
+	lv.D, lv.C, lv.SharksR2 = leastSquares2(dS, s1, s2)
+	return lv
+}
+
+// leastSquares2 fits y ≈ b1*x1 + b2*x2 (no intercept) and returns the
+// coefficients and the coefficient of determination R².
+func leastSquares2(y, x1, x2 []float64) (b1, b2, r2 float64) {
+	var s11, s12, s22, s1y, s2y float64
+	for i := range y {
+		s11 += x1[i] * x1[i]
+		s12 += x1[i] * x2[i]
+		s22 += x2[i] * x2[i]
+		s1y += x1[i] * y[i]
+		s2y += x2[i] * y[i]
+	}
+	det := s11*s22 - s12*s12
+	if det == 0 {
+		return 0, 0, 0
+	}
+	b1 = (s1y*s22 - s2y*s12) / det
+	b2 = (s2y*s11 - s1y*s12) / det
+
+	my := mean(y)
+	var ssRes, ssTot float64
+	for i := range y {
+		e := y[i] - b1*x1[i] - b2*x2[i]
+		ssRes += e * e
+		ssTot += (y[i] - my) * (y[i] - my)
+	}
+	if ssTot > 0 {
+		r2 = 1 - ssRes/ssTot
+	}
+	return
+}
+
+// runAnalyze implements the "analyze" subcommand. It reads a stats CSV in
+// the step,fish,sharks format and reports the oscillation period (by
+// autocorrelation and FFT), amplitude and mean of each population, how far
+// shark peaks lag behind fish peaks, and a Lotka–Volterra fit.
+func runAnalyze(args []string) {
+	fs := flag.NewFlagSet("analyze", flag.ExitOnError)
+	statsFile := fs.String("stats", "", "Population file written with -csv (CSV or .jsonl)")
+	skip := fs.Float64("skip", 0.1, "Fraction of the run to discard as the initial transient")
+	fs.Parse(args)
+
+	if *statsFile == "" && fs.NArg() == 1 {
+		*statsFile = fs.Arg(0)
+	}
+	if *statsFile == "" {
+		fmt.Println("Error: analyze needs a stats CSV (-stats=file)")
+		os.Exit(1)
+	}
+	if *skip < 0 || *skip >= 1 {
+		fmt.Println("Error: -skip must be in [0, 1)")
+		os.Exit(1)
+	}
+
+	s, err := readStats(*statsFile)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	from := int(*skip * float64(len(s.Step)))
+	fish, sharks := s.Fish[from:], s.Sharks[from:]
+	if len(fish) < 8 {
+		fmt.Println("Error: not enough steps to analyse")
+		os.Exit(1)
+	}
+
+	fmt.Printf("Wa-Tor Analysis: %s\n", *statsFile)
+	fmt.Println("----------------")
+	fmt.Printf("Steps analysed : %d (skipped first %d)\n\n", len(fish), from)
+
+	fmt.Printf("%-8s %10s %10s %12s %12s\n", "", "mean", "amplitude", "period(acf)", "period(fft)")
+	for _, sp := range []struct {
+		name string
+		x    []float64
+	}{{"Fish", fish}, {"Sharks", sharks}} {
+		fmt.Printf("%-8s %10.1f %10.1f %12d %12.1f\n",
+			sp.name, mean(sp.x), amplitude(sp.x), dominantPeriod(sp.x), fftPeriod(sp.x))
+	}
+
+	period := dominantPeriod(fish)
+	if period > 0 {
+		lag := peakLag(fish, sharks, period)
+		fmt.Printf("\nShark peaks lag fish peaks by %d steps (%.0f° of the cycle)\n",
+			lag, 360*float64(lag)/float64(period))
+	} else {
+		fmt.Println("\nNo clear oscillation in the fish population")
+	}
+
+	lv := fitLotkaVolterra(fish, sharks)
+	fmt.Println("\nLotka–Volterra fit  dF/dt = aF - bFS,  dS/dt = dFS - cS")
+	fmt.Printf("  a = %.5g   b = %.5g   (fish R²   = %.3f)\n", lv.A, lv.B, lv.FishR2)
+	fmt.Printf("  c = %.5g   d = %.5g   (sharks R² = %.3f)\n", lv.C, lv.D, lv.SharksR2)
+	if lv.A > 0 && lv.B > 0 && lv.C > 0 && lv.D > 0 {
+		fmt.Printf("  equilibrium: fish = %.1f, sharks = %.1f\n", lv.C/lv.D, lv.A/lv.B)
+		fmt.Printf("  small-oscillation period 2π/√(ac) = %.1f steps\n", 2*math.Pi/math.Sqrt(lv.A*lv.C))
+	}
+}
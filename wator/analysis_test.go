@@ -0,0 +1,149 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+import (
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"testing"
+)
+
+// sine returns n samples of mean + amp*sin(2πt/period + phase).
+func sine(n int, period, mean, amp, phase float64) []float64 {
+	x := make([]float64, n)
+	for t := range x {
+		x[t] = mean + amp*math.Sin(2*math.Pi*float64(t)/period+phase)
+	}
+	return x
+}
+
+// TestAutocorrelation checks the normalisation and the series without
+// any correlation structure.
+func TestAutocorrelation(t *testing.T) {
+	tests := []struct {
+		name   string
+		x      []float64
+		maxLag int
+		want   []float64 // nil = no result
+	}{
+		{"constant", []float64{3, 3, 3, 3}, 2, nil},
+		{"single sample", []float64{1}, 3, nil},
+		{"zero lags", []float64{1, 2, 3}, 0, nil},
+		{"alternating", []float64{1, -1, 1, -1}, 2, []float64{1, -0.75, 0.5}},
+		{"lag clipped to the series", []float64{0, 1}, 5, []float64{1, -0.5}},
+	}
+	for _, tt := range tests {
+		got := autocorrelation(tt.x, tt.maxLag)
+		if (got == nil) != (tt.want == nil) || len(got) != len(tt.want) {
+			t.Errorf("%s: autocorrelation = %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if math.Abs(got[i]-tt.want[i]) > 1e-12 {
+				t.Errorf("%s: autocorrelation = %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+// TestPeriodDetection checks dominantPeriod and fftPeriod on series with
+// a known period, and that both give 0 when nothing oscillates.
+func TestPeriodDetection(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	noisy := sine(600, 40, 500, 100, 0.3)
+	for i := range noisy {
+		noisy[i] += rng.NormFloat64() * 10
+	}
+	extinct := make([]float64, 300)
+	copy(extinct, sine(100, 25, 50, 40, 0))
+
+	tests := []struct {
+		name      string
+		x         []float64
+		autoWant  int     // expected dominantPeriod
+		fftWant   float64 // expected fftPeriod
+		fftMargin float64 // allowed relative error of fftPeriod
+	}{
+		{"period 16", sine(512, 16, 100, 50, 0), 16, 16, 0},
+		{"period 32", sine(512, 32, 10, 5, 1), 32, 32, 0},
+		{"period 37", sine(740, 37, 300, 80, 0), 37, 37, 0.05},
+		{"noisy period 40", noisy, 40, 40, 0.05},
+		{"constant", make([]float64, 200), 0, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := dominantPeriod(tt.x); abs(got-tt.autoWant) > 1 {
+			t.Errorf("%s: dominantPeriod = %d, want %d", tt.name, got, tt.autoWant)
+		}
+		got := fftPeriod(tt.x)
+		if math.Abs(got-tt.fftWant) > tt.fftMargin*tt.fftWant+1e-9 {
+			t.Errorf("%s: fftPeriod = %.2f, want %.2f", tt.name, got, tt.fftWant)
+		}
+	}
+	if got := dominantPeriod(extinct); got != 0 {
+		t.Errorf("series ending in extinction: dominantPeriod = %d, want 0", got)
+	}
+}
+
+// TestFFT compares fft with the discrete Fourier transform computed from
+// its definition.
+func TestFFT(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for _, n := range []int{1, 2, 4, 8, 64} {
+		a := make([]complex128, n)
+		for i := range a {
+			a[i] = complex(rng.Float64(), rng.Float64())
+		}
+		want := make([]complex128, n)
+		for k := range want {
+			for j, v := range a {
+				want[k] += v * cmplx.Exp(complex(0, -2*math.Pi*float64(j*k)/float64(n)))
+			}
+		}
+		fft(a)
+		for k := range a {
+			if cmplx.Abs(a[k]-want[k]) > 1e-9 {
+				t.Errorf("n = %d: X[%d] = %v, want %v", n, k, a[k], want[k])
+			}
+		}
+	}
+}
+
+// TestPeakLag checks that the lag of a delayed copy of a series is found.
+func TestPeakLag(t *testing.T) {
+	x := sine(400, 50, 0, 1, 0)
+	for _, delay := range []int{0, 3, 12} {
+		y := sine(400, 50, 0, 1, -2*math.Pi*float64(delay)/50)
+		if got := peakLag(x, y, 25); got != delay {
+			t.Errorf("delay %d: peakLag = %d", delay, got)
+		}
+	}
+}
+
+// TestFitLotkaVolterra fits series generated by the discrete equations
+// themselves, which must give back their coefficients exactly.
+func TestFitLotkaVolterra(t *testing.T) {
+	const a, b, c, d = 0.05, 0.001, 0.04, 0.0004
+	fish, sharks := []float64{120}, []float64{40}
+	for i := 0; i < 300; i++ {
+		F, S := fish[i], sharks[i]
+		fish = append(fish, F+a*F-b*F*S)
+		sharks = append(sharks, S+d*F*S-c*S)
+	}
+	lv := fitLotkaVolterra(fish, sharks)
+	for _, v := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"A", lv.A, a}, {"B", lv.B, b}, {"C", lv.C, c}, {"D", lv.D, d},
+		{"fish R²", lv.FishR2, 1}, {"sharks R²", lv.SharksR2, 1},
+	} {
+		if math.Abs(v.got-v.want) > 1e-6*math.Max(1, v.want) {
+			t.Errorf("%s = %g, want %g", v.name, v.got, v.want)
+		}
+	}
+}
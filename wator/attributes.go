@@ -0,0 +1,49 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+import "sync/atomic"
+
+// CellInfo describes the contents of a cell for the attribute views of
+// the graphics window.
+type CellInfo struct {
+	Kind   CellType
+	Breed  int // chronons since the creature last reproduced
+	Energy int // shark energy (0 for fish)
+	Age    int // chronons since the creature was born (-1 = not tracked)
+}
+
+// predationMap counts, for every cell, how many fish sharks have eaten
+// there. It is only allocated once tracking is enabled, as most runs never
+// look at it. Increments are atomic because neighbouring rows can be
+// updated by different goroutines.
+type predationMap struct {
+	counts []uint32
+	size   int
+}
+
+// enable starts counting predation events in a world of the given size.
+func (m *predationMap) enable(size int) {
+	if m.counts == nil {
+		m.size = size
+		m.counts = make([]uint32, size*size)
+	}
+}
+
+// add records a fish eaten at (x, y), if tracking is enabled.
+func (m *predationMap) add(x, y int) {
+	if m.counts != nil {
+		atomic.AddUint32(&m.counts[y*m.size+x], 1)
+	}
+}
+
+// at returns the number of fish eaten at (x, y) since tracking started.
+func (m *predationMap) at(x, y int) int {
+	if m.counts == nil {
+		return 0
+	}
+	return int(atomic.LoadUint32(&m.counts[y*m.size+x]))
+}
@@ -0,0 +1,213 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BenchResult holds the timings of all measured runs for one thread count.
+type BenchResult struct {
+	Threads int
+	Times   []time.Duration
+	Steps   []int    // chronons simulated by each run
+	Stops   []string // early stop reason of each run ("" = ran all steps)
+}
+
+// Mean returns the average run time in seconds.
+func (b BenchResult) Mean() float64 {
+	sum := 0.0
+	for _, t := range b.Times {
+		sum += t.Seconds()
+	}
+	return sum / float64(len(b.Times))
+}
+
+// StdDev returns the sample standard deviation of the run times in seconds
+// (0 when there is only one run).
+func (b BenchResult) StdDev() float64 {
+	if len(b.Times) < 2 {
+		return 0
+	}
+	mean := b.Mean()
+	sum := 0.0
+	for _, t := range b.Times {
+		d := t.Seconds() - mean
+		sum += d * d
+	}
+	return math.Sqrt(sum / float64(len(b.Times)-1))
+}
+
+// runBench implements the "bench" subcommand. It runs the same simulation
+// configuration for every thread count in -threadList, -reps times each
+// after -warmup unrecorded runs, and reports the mean and standard
+// deviation of the run time together with the speedup and efficiency
+// relative to the first thread count. The results are printed as a
+// Markdown table like the one in RESULTS.md and written to CSV.
+func runBench(args []string) {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	p := Params{}
+	registerParamFlags(fs, &p)
+	config := registerConfigFlags(fs)
+
+	threadList := fs.String("threadList", "1,2,4,8", "Comma-separated thread counts to measure")
+	reps := fs.Int("reps", 3, "Measured runs per thread count")
+	warmup := fs.Int("warmup", 1, "Unrecorded warm-up runs per thread count")
+	csvOut := fs.String("benchCSV", "bench.csv", "CSV file for the results (empty = none)")
+	mdOut := fs.String("benchMD", "bench.md", "Markdown file for the results table (empty = none)")
+
+	fs.Parse(args)
+	config.apply(fs, &p)
+	checkParams(p)
+
+	threads, err := parseIntList(*threadList)
+	if err != nil || len(threads) == 0 || slices.Min(threads) < 1 {
+		fmt.Println("Error: invalid -threadList:", *threadList)
+		os.Exit(1)
+	}
+	p.Threads = slices.Max(threads)
+	checkParams(p) // every thread count must fit the grid
+	if *reps < 1 || *warmup < 0 {
+		fmt.Println("Error: -reps must be >= 1 and -warmup >= 0")
+		os.Exit(1)
+	}
+
+	p = p.headless()
+	// Every run starts from the same layout, so the timings compare thread
+	// counts rather than different random worlds.
+	p.Seed = resolveSeed(p.Seed)
+
+	fmt.Println("Wa-Tor Benchmark")
+	fmt.Println("----------------")
+	fmt.Printf("GridSize    : %d x %d\n", p.GridSize, p.GridSize)
+	fmt.Printf("Fish/Sharks : %d / %d\n", p.NumFish, p.NumShark)
+	fmt.Printf("Steps       : %d\n", p.Steps)
+	fmt.Printf("Seed        : %d\n", p.Seed)
+	fmt.Printf("Threads     : %v (%d warm-up + %d measured runs each)\n", threads, *warmup, *reps)
+
+	results := make([]BenchResult, 0, len(threads))
+	for _, t := range threads {
+		p.Threads = t
+		r := BenchResult{Threads: t}
+		for i := 0; i < *warmup+*reps; i++ {
+			world, err := newOcean(p)
+			if err != nil {
+				fmt.Println("Error creating world:", err)
+				os.Exit(1)
+			}
+			res := simulate(world, p, nil)
+			if i >= *warmup {
+				r.Times = append(r.Times, res.Elapsed)
+				r.Steps = append(r.Steps, res.Steps)
+				r.Stops = append(r.Stops, res.StopReason)
+			}
+		}
+		fmt.Printf("threads=%d  mean=%.4fs  stddev=%.4fs\n", t, r.Mean(), r.StdDev())
+		for i, reason := range r.Stops {
+			if reason != "" {
+				fmt.Printf("  run %d stopped early at step %d: %s\n", i+1, r.Steps[i], reason)
+			}
+		}
+		results = append(results, r)
+	}
+
+	table := benchTable(results)
+	fmt.Println()
+	fmt.Print(table)
+
+	if *mdOut != "" {
+		if err := os.WriteFile(*mdOut, []byte(table), 0o644); err != nil {
+			fmt.Println("Error writing Markdown table:", err)
+			os.Exit(1)
+		}
+	}
+	if *csvOut != "" {
+		if err := writeBenchCSV(*csvOut, results); err != nil {
+			fmt.Println("Error writing CSV file:", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// speedup returns the speedup of r relative to the baseline result and the
+// parallel efficiency, i.e. the speedup divided by the increase in threads.
+func speedup(base, r BenchResult) (float64, float64) {
+	s := base.Mean() / r.Mean()
+	return s, s * float64(base.Threads) / float64(r.Threads)
+}
+
+// benchTable formats the results as a Markdown table in the style of
+// RESULTS.md, extended with the spread and scaling columns.
+func benchTable(results []BenchResult) string {
+	var b strings.Builder
+	fmt.Fprintln(&b, "| Threads | Time (seconds) | Std dev (s) | Speedup | Efficiency |")
+	fmt.Fprintln(&b, "| ------: | -------------: | ----------: | ------: | ---------: |")
+	for _, r := range results {
+		s, e := speedup(results[0], r)
+		fmt.Fprintf(&b, "| %7d | %12.9f s | %11.4f | %7.2f | %9.1f%% |\n",
+			r.Threads, r.Mean(), r.StdDev(), s, 100*e)
+	}
+	return b.String()
+}
+
+// writeBenchCSV writes one row per thread count with the summary
+// statistics of its runs.
+func writeBenchCSV(path string, results []BenchResult) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	fmt.Fprintln(w, "threads,runs,mean_s,stddev_s,speedup,efficiency,mean_steps,early_stops")
+	for _, r := range results {
+		s, e := speedup(results[0], r)
+		stops := 0
+		for _, reason := range r.Stops {
+			if reason != "" {
+				stops++
+			}
+		}
+		fmt.Fprintf(w, "%d,%d,%.6f,%.6f,%.4f,%.4f,%.1f,%d\n",
+			r.Threads, len(r.Times), r.Mean(), r.StdDev(), s, e, mean(intsToFloats(r.Steps)), stops)
+	}
+	return w.Flush()
+}
+
+// intsToFloats converts a slice of ints to float64s.
+func intsToFloats(v []int) []float64 {
+	out := make([]float64, len(v))
+	for i, x := range v {
+		out[i] = float64(x)
+	}
+	return out
+}
+
+// parseIntList parses a comma-separated list of integers such as "1,2,4,8".
+func parseIntList(s string) ([]int, error) {
+	var out []int
+	for _, f := range strings.Split(s, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, n)
+	}
+	return out, nil
+}
@@ -0,0 +1,233 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"image/png"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Recording formats accepted by -recordFormat.
+const (
+	RecordGIF = "gif" // one animated GIF playing at the speed it was recorded
+	RecordPNG = "png" // a directory of numbered PNG frames, e.g. for a video encoder
+)
+
+// gifMaxFrames is the longest GIF recording. Its frames are kept in
+// memory until it stops, so recording ends by itself at this length.
+const gifMaxFrames = 500
+
+// newCapturePath reserves the first unused name prefix-001ext,
+// prefix-002ext, ... in dir, creating dir if needed. The name is reserved
+// by creating an empty file, or a directory when ext is empty, so two
+// captures never get the same name.
+func newCapturePath(dir, prefix, ext string) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	for i := 1; ; i++ {
+		path := filepath.Join(dir, fmt.Sprintf("%s-%03d%s", prefix, i, ext))
+		var err error
+		if ext == "" {
+			err = os.Mkdir(path, 0o755)
+		} else {
+			var f *os.File
+			if f, err = os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644); err == nil {
+				err = f.Close()
+			}
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return path, err
+		}
+	}
+}
+
+// savePNG writes img to path as a PNG, trading size for speed so that
+// recording keeps up with the window.
+func savePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	enc := png.Encoder{CompressionLevel: png.BestSpeed}
+	if err := enc.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// recordFrame is one frame of a recording and when it was shown.
+type recordFrame struct {
+	img *image.RGBA
+	at  time.Time
+}
+
+// recorder writes the frames of a recording on its own goroutine, so
+// encoding does not hold up the window. Frames are queued with add; stop
+// waits for the last one to be written.
+type recorder struct {
+	path   string // GIF file or directory of PNG frames
+	format string
+	frames chan recordFrame
+	done   chan error
+	count  int // frames queued
+}
+
+// startRecording reserves the next rec-NNN.gif file or rec-NNN directory
+// in dir and starts writing the frames added to it.
+func startRecording(dir, format string) (*recorder, error) {
+	ext := ".gif"
+	if format == RecordPNG {
+		ext = ""
+	}
+	path, err := newCapturePath(dir, "rec", ext)
+	if err != nil {
+		return nil, err
+	}
+	r := &recorder{
+		path:   path,
+		format: format,
+		frames: make(chan recordFrame, 8),
+		done:   make(chan error, 1),
+	}
+	if format == RecordPNG {
+		go func() { r.done <- r.writePNGs() }()
+	} else {
+		go func() { r.done <- r.writeGIF() }()
+	}
+	return r, nil
+}
+
+// add queues a frame, waiting if the encoder is behind. The frame must
+// not be changed afterwards. It returns false, without queueing the
+// frame, once a GIF recording is full.
+func (r *recorder) add(img *image.RGBA, at time.Time) bool {
+	if r.format == RecordGIF && r.count >= gifMaxFrames {
+		return false
+	}
+	r.frames <- recordFrame{img, at}
+	r.count++
+	return true
+}
+
+// stop ends the recording and returns once every frame has been written.
+func (r *recorder) stop() error {
+	close(r.frames)
+	return <-r.done
+}
+
+// writePNGs saves each frame as frame-00000.png, frame-00001.png, ... in
+// the recording's directory. After an error the remaining frames are
+// dropped, so add does not block.
+func (r *recorder) writePNGs() error {
+	var first error
+	i := 0
+	for f := range r.frames {
+		if first == nil {
+			first = savePNG(filepath.Join(r.path, fmt.Sprintf("frame-%05d.png", i)), f.img)
+		}
+		i++
+	}
+	return first
+}
+
+// writeGIF converts the frames to the GIF palette as they arrive and
+// encodes the animation when the recording stops. Each frame is shown for
+// as long as it was shown in the window. All frames have the size of the
+// first; if the window was resized they are cropped or padded.
+func (r *recorder) writeGIF() error {
+	anim := &gif.GIF{}
+	pal := gifPalette()
+	cache := map[uint32]uint8{}
+	var bounds image.Rectangle
+	var last time.Time
+	for f := range r.frames {
+		if len(anim.Image) == 0 {
+			bounds = f.img.Bounds()
+		} else {
+			anim.Delay = append(anim.Delay, gifDelay(f.at.Sub(last)))
+		}
+		anim.Image = append(anim.Image, quantize(f.img, bounds, pal, cache))
+		last = f.at
+	}
+	if len(anim.Image) == 0 {
+		return os.Remove(r.path)
+	}
+	final := 10 // the last frame has nothing to time it, so show it for 0.1 s
+	if n := len(anim.Delay); n > 0 {
+		final = anim.Delay[n-1]
+	}
+	anim.Delay = append(anim.Delay, final)
+
+	f, err := os.Create(r.path)
+	if err != nil {
+		return err
+	}
+	if err := gif.EncodeAll(f, anim); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// gifDelay converts the time a frame was shown into GIF hundredths of a
+// second. Viewers show shorter delays than 2 as 10, so they are rounded
+// up to 2.
+func gifDelay(d time.Duration) int {
+	return max(2, int((d+5*time.Millisecond)/(10*time.Millisecond)))
+}
+
+// gifPalette returns the 256 colours of GIF frames: the colours of water,
+// fish, sharks and rocks, black and white, so the normal view is
+// reproduced exactly, followed by the Plan 9 palette for everything else.
+func gifPalette() color.Palette {
+	pal := color.Palette{color.Black, color.White}
+	for _, c := range [][3]int{waterRGB, fishRGB, sharkRGB, rockRGB} {
+		pal = append(pal, color.RGBA{uint8(c[0]), uint8(c[1]), uint8(c[2]), 255})
+	}
+	for _, c := range palette.Plan9 {
+		if len(pal) == 256 {
+			break
+		}
+		if pal[pal.Index(c)] != c {
+			pal = append(pal, c)
+		}
+	}
+	return pal
+}
+
+// quantize converts the part of img inside bounds to pal. A frame has
+// few distinct colours, so the nearest palette entry of each is found
+// once and kept in cache, which is shared by all frames of a recording.
+func quantize(img *image.RGBA, bounds image.Rectangle, pal color.Palette, cache map[uint32]uint8) *image.Paletted {
+	out := image.NewPaletted(bounds, pal)
+	r := bounds.Intersect(img.Bounds())
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		src := img.Pix[img.PixOffset(r.Min.X, y):]
+		dst := out.Pix[out.PixOffset(r.Min.X, y):]
+		for x := 0; x < r.Dx(); x++ {
+			p := src[4*x : 4*x+3 : 4*x+3]
+			key := uint32(p[0])<<16 | uint32(p[1])<<8 | uint32(p[2])
+			i, ok := cache[key]
+			if !ok {
+				i = uint8(pal.Index(color.RGBA{p[0], p[1], p[2], 255}))
+				cache[key] = i
+			}
+			dst[x] = i
+		}
+	}
+	return out
+}
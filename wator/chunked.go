@@ -0,0 +1,504 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// chunkSize is the width and height, in cells, of one storage chunk.
+const chunkSize = 256
+
+// Cell is the compact 16-bit encoding of a grid cell used by ChunkedWorld.
+// The low 2 bits hold the CellType, the next 7 bits the breed counter and
+// the top 7 bits the shark energy. The zero value is an empty cell.
+type Cell uint16
+
+const (
+	cellKindMask    = 0x3
+	cellBreedShift  = 2
+	cellEnergyShift = 9
+
+	// maxCellCounter is the largest breed counter or energy a Cell can hold.
+	maxCellCounter = 0x7f
+)
+
+// makeCell packs a creature into a Cell. The breed counter saturates at
+// maxCellCounter, which is harmless because a creature only checks whether
+// it has reached its breed time. Energy never exceeds -starve, which
+// Validate keeps within maxCellCounter; it is capped too, so it can never
+// spill over into the other fields.
+func makeCell(kind CellType, breed, energy int) Cell {
+	breed = min(breed, maxCellCounter)
+	energy = min(energy, maxCellCounter)
+	return Cell(kind) | Cell(breed)<<cellBreedShift | Cell(energy)<<cellEnergyShift
+}
+
+// Kind returns the CellType stored in the cell.
+func (c Cell) Kind() CellType { return CellType(c & cellKindMask) }
+
+// Breed returns the chronons since the creature last reproduced.
+func (c Cell) Breed() int { return int(c>>cellBreedShift) & maxCellCounter }
+
+// Energy returns the remaining energy of a shark (0 for fish).
+func (c Cell) Energy() int { return int(c>>cellEnergyShift) & maxCellCounter }
+
+// chunk is one chunkSize x chunkSize block of cells together with the
+// number of fish and sharks it held after the last step. Chunks with no
+// creatures of a kind are skipped during that kind's phase.
+type chunk struct {
+	cells  [chunkSize * chunkSize]Cell
+	fish   int
+	sharks int
+}
+
+// ChunkedWorld is an alternative to World for very large grids. Instead of
+// a [][]*Creature it stores the ocean as 256x256 chunks of 16-bit cells,
+// double-buffered, so a cell costs 4 bytes in total and no per-creature
+// allocations are made. It follows exactly the same rules as World.
+type ChunkedWorld struct {
+	Size   int
+	Params Params
+
+	chunksPerSide int
+	cur, next     []*chunk
+	rng           *rand.Rand
+	phases        PhaseTimes // accumulated Fish, Shark and Merge times
+	rocks         rockMask   // obstacles; rock cells are stored as empty
+	predation     predationMap
+}
+
+// NewChunkedWorld creates a chunked toroidal world with randomly placed
+// fish and sharks, seeded like NewWorld. Creatures are placed by selection sampling in a single
+// pass over the grid, which avoids building a permutation of every cell.
+// Invalid parameters are reported like NewWorld.
+func NewChunkedWorld(p Params) (*ChunkedWorld, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	p.Seed = resolveSeed(p.Seed)
+	totalCells := p.GridSize * p.GridSize
+
+	n := (p.GridSize + chunkSize - 1) / chunkSize
+	w := &ChunkedWorld{
+		Size:          p.GridSize,
+		Params:        p,
+		chunksPerSide: n,
+		cur:           make([]*chunk, n*n),
+		next:          make([]*chunk, n*n),
+		rng:           rand.New(rand.NewSource(p.Seed)),
+	}
+	for i := range w.cur {
+		w.cur[i] = &chunk{}
+		w.next[i] = &chunk{}
+	}
+
+	fishLeft, sharksLeft := p.NumFish, p.NumShark
+	for i := 0; i < totalCells && fishLeft+sharksLeft > 0; i++ {
+		// Occupy this cell with probability (creatures left)/(cells left),
+		// then choose the kind in proportion to what is still to be placed.
+		r := w.rng.Intn(totalCells - i)
+		if r >= fishLeft+sharksLeft {
+			continue
+		}
+		x, y := i%p.GridSize, i/p.GridSize
+		if r < fishLeft {
+			w.set(w.cur, x, y, makeCell(FishCell, 0, 0))
+			fishLeft--
+		} else {
+			w.set(w.cur, x, y, makeCell(SharkCell, 0, p.Starve))
+			sharksLeft--
+		}
+	}
+	w.recount(1)
+
+	return w, nil
+}
+
+// Dim returns the width and height of the grid.
+func (w *ChunkedWorld) Dim() int {
+	return w.Size
+}
+
+// locate returns the chunk index and the offset within the chunk of (x, y).
+func (w *ChunkedWorld) locate(x, y int) (int, int) {
+	ci := (y/chunkSize)*w.chunksPerSide + x/chunkSize
+	off := (y%chunkSize)*chunkSize + x%chunkSize
+	return ci, off
+}
+
+// get returns the cell at (x, y) in the given buffer.
+func (w *ChunkedWorld) get(buf []*chunk, x, y int) Cell {
+	ci, off := w.locate(x, y)
+	return buf[ci].cells[off]
+}
+
+// set stores a cell at (x, y) in the given buffer.
+func (w *ChunkedWorld) set(buf []*chunk, x, y int, c Cell) {
+	ci, off := w.locate(x, y)
+	buf[ci].cells[off] = c
+}
+
+// CellAt returns the CellType at coordinates (x, y).
+func (w *ChunkedWorld) CellAt(x, y int) CellType {
+	if w.rocks.has(x, y) {
+		return RockCell
+	}
+	return w.get(w.cur, x, y).Kind()
+}
+
+// Info returns the kind, breed counter and energy of the creature at
+// (x, y). A Cell has no room for an age, so Age is always -1.
+func (w *ChunkedWorld) Info(x, y int) CellInfo {
+	c := w.get(w.cur, x, y)
+	return CellInfo{Kind: w.CellAt(x, y), Breed: c.Breed(), Energy: c.Energy(), Age: -1}
+}
+
+// TrackPredation starts counting the fish eaten in each cell.
+func (w *ChunkedWorld) TrackPredation() { w.predation.enable(w.Size) }
+
+// SetParams replaces the parameters of the world between two steps, like
+// World.SetParams. The times must fit the 7-bit counters of a Cell.
+func (w *ChunkedWorld) SetParams(p Params) { w.Params = p }
+
+// Predation returns the number of fish eaten at (x, y) since
+// TrackPredation was called.
+func (w *ChunkedWorld) Predation(x, y int) int { return w.predation.at(x, y) }
+
+// SetCell replaces the contents of cell (x, y) like World.SetCell, keeping
+// the per-chunk counts up to date.
+func (w *ChunkedWorld) SetCell(x, y int, kind CellType) {
+	ci, off := w.locate(x, y)
+	ch := w.cur[ci]
+	switch ch.cells[off].Kind() {
+	case FishCell:
+		ch.fish--
+	case SharkCell:
+		ch.sharks--
+	}
+
+	ch.cells[off] = 0
+	switch kind {
+	case FishCell:
+		ch.cells[off] = makeCell(FishCell, 0, 0)
+		ch.fish++
+	case SharkCell:
+		ch.cells[off] = makeCell(SharkCell, 0, w.Params.Starve)
+		ch.sharks++
+	}
+	w.rocks.set(x, y, w.Size, kind == RockCell)
+}
+
+// PhaseTimes returns the time spent so far in the fish and shark phases and
+// in clearing, swapping and recounting the buffers.
+func (w *ChunkedWorld) PhaseTimes() PhaseTimes {
+	return w.phases
+}
+
+// Count returns the total number of fish and sharks currently in the world.
+// The totals are kept per chunk, so this does not scan the grid.
+func (w *ChunkedWorld) Count() (fish int, sharks int) {
+	for _, c := range w.cur {
+		fish += c.fish
+		sharks += c.sharks
+	}
+	return
+}
+
+// Step performs one chronon of the simulation sequentially: all fish are
+// updated, then all sharks, writing into the back buffer which is then
+// swapped into place.
+func (w *ChunkedWorld) Step() {
+	t := time.Now()
+	w.clearNext()
+	t = w.addPhase(&w.phases.Merge, t)
+	for _, kind := range []CellType{FishCell, SharkCell} {
+		for cy := 0; cy < w.chunksPerSide; cy++ {
+			w.updateBand(kind, cy, w.rng)
+		}
+		t = w.addPhase(w.kindPhase(kind), t)
+	}
+	w.swap(1)
+	w.addPhase(&w.phases.Merge, t)
+}
+
+// StepParallel performs one chronon using multiple goroutines. Work is
+// split by chunk rows. A creature only touches cells of its own row and
+// the cells directly above and below, so chunk rows that cannot reach the
+// same cells are updated at the same time into the shared back buffer, in
+// the passes chosen by bandPasses.
+func (w *ChunkedWorld) StepParallel(threads int) {
+	if threads <= 1 || w.chunksPerSide < 2 {
+		w.Step()
+		return
+	}
+
+	t := time.Now()
+	w.clearNext()
+	t = w.addPhase(&w.phases.Merge, t)
+	for _, kind := range []CellType{FishCell, SharkCell} {
+		for _, pass := range w.bandPasses() {
+			w.runBands(kind, pass, threads)
+		}
+		t = w.addPhase(w.kindPhase(kind), t)
+	}
+	w.swap(threads)
+	w.addPhase(&w.phases.Merge, t)
+}
+
+// kindPhase returns the phase timer for the given kind of creature.
+func (w *ChunkedWorld) kindPhase(kind CellType) *time.Duration {
+	if kind == FishCell {
+		return &w.phases.Fish
+	}
+	return &w.phases.Shark
+}
+
+// addPhase adds the time elapsed since start to d and returns the current
+// time, so consecutive phases can be timed back to back.
+func (w *ChunkedWorld) addPhase(d *time.Duration, start time.Time) time.Time {
+	now := time.Now()
+	*d += now.Sub(start)
+	return now
+}
+
+// bandPasses groups the chunk rows into passes whose rows never reach
+// the same cells, putting each row into the first pass it does not clash
+// with. That gives even rows, then odd rows, plus a pass for a lone last
+// row when the count is odd, because it wraps round to touch row 0.
+func (w *ChunkedWorld) bandPasses() [][]int {
+	var passes [][]int
+	for cy := 0; cy < w.chunksPerSide; cy++ {
+		i := 0
+		for ; i < len(passes); i++ {
+			ok := true
+			for _, other := range passes[i] {
+				if w.bandsClash(cy, other) {
+					ok = false
+					break
+				}
+			}
+			if ok {
+				break
+			}
+		}
+		if i == len(passes) {
+			passes = append(passes, nil)
+		}
+		passes[i] = append(passes[i], cy)
+	}
+	return passes
+}
+
+// bandsClash reports whether chunk rows a and b can touch the same cells:
+// when they are neighbours on the torus, or when the last row is a single
+// cell tall, so the rows on either side of it (the one before it and row
+// 0) both reach that one line of cells.
+func (w *ChunkedWorld) bandsClash(a, b int) bool {
+	n := w.chunksPerSide
+	if d := (a - b + n) % n; d == 1 || d == n-1 {
+		return true
+	}
+	lone := w.Size%chunkSize == 1
+	return lone && a != b && (a == 0 || a == n-2) && (b == 0 || b == n-2)
+}
+
+// runBands updates the given chunk rows for one kind of creature using up
+// to threads goroutines, each with its own random number generator.
+func (w *ChunkedWorld) runBands(kind CellType, rows []int, threads int) {
+	if threads > len(rows) {
+		threads = len(rows)
+	}
+
+	var wg sync.WaitGroup
+	for t := 0; t < threads; t++ {
+		rng := rand.New(rand.NewSource(w.rng.Int63()))
+		wg.Add(1)
+		go func(t int, rng *rand.Rand) {
+			defer wg.Done()
+			for i := t; i < len(rows); i += threads {
+				w.updateBand(kind, rows[i], rng)
+			}
+		}(t, rng)
+	}
+	wg.Wait()
+}
+
+// updateBand updates every creature of the given kind in chunk row cy,
+// skipping chunks that hold none.
+func (w *ChunkedWorld) updateBand(kind CellType, cy int, rng *rand.Rand) {
+	y0 := cy * chunkSize
+	y1 := min(y0+chunkSize, w.Size)
+
+	for cx := 0; cx < w.chunksPerSide; cx++ {
+		ch := w.cur[cy*w.chunksPerSide+cx]
+		if (kind == FishCell && ch.fish == 0) || (kind == SharkCell && ch.sharks == 0) {
+			continue
+		}
+
+		x0 := cx * chunkSize
+		x1 := min(x0+chunkSize, w.Size)
+		for y := y0; y < y1; y++ {
+			row := ch.cells[(y-y0)*chunkSize:]
+			for x := x0; x < x1; x++ {
+				c := row[x-x0]
+				if c.Kind() != kind {
+					continue
+				}
+				if kind == FishCell {
+					w.updateFish(x, y, c, rng)
+				} else {
+					w.updateShark(x, y, c, rng)
+				}
+			}
+		}
+	}
+}
+
+// clearNext empties the back buffer. Chunks that held no creatures when
+// they were last counted are already empty and are left alone.
+func (w *ChunkedWorld) clearNext() {
+	for _, c := range w.next {
+		if c.fish+c.sharks > 0 {
+			clear(c.cells[:])
+			c.fish, c.sharks = 0, 0
+		}
+	}
+}
+
+// swap makes the back buffer current and recounts its chunks.
+func (w *ChunkedWorld) swap(threads int) {
+	w.cur, w.next = w.next, w.cur
+	w.recount(threads)
+}
+
+// recount refreshes the per-chunk fish and shark totals of the current
+// buffer, spreading the chunks over up to threads goroutines.
+func (w *ChunkedWorld) recount(threads int) {
+	threads = max(1, min(threads, len(w.cur)))
+
+	var wg sync.WaitGroup
+	for t := 0; t < threads; t++ {
+		wg.Add(1)
+		go func(t int) {
+			defer wg.Done()
+			for i := t; i < len(w.cur); i += threads {
+				ch := w.cur[i]
+				ch.fish, ch.sharks = 0, 0
+				for _, c := range ch.cells {
+					switch c.Kind() {
+					case FishCell:
+						ch.fish++
+					case SharkCell:
+						ch.sharks++
+					}
+				}
+			}
+		}(t)
+	}
+	wg.Wait()
+}
+
+// neighbours returns the 4-neighbour coordinates (N,E,S,W) around (x, y),
+// using toroidal wrapping. It returns an array to avoid allocating.
+func (w *ChunkedWorld) neighbours(x, y int) [4][2]int {
+	return [4][2]int{
+		{x, (y - 1 + w.Size) % w.Size},
+		{(x + 1) % w.Size, y},
+		{x, (y + 1) % w.Size},
+		{(x - 1 + w.Size) % w.Size, y},
+	}
+}
+
+// neighboursOfKind stores the neighbours of (x, y) that hold the given kind
+// in the current buffer into out and returns how many there are. Rocks
+// are never counted as Empty.
+func (w *ChunkedWorld) neighboursOfKind(x, y int, kind CellType, out *[4][2]int) int {
+	n := 0
+	for _, nb := range w.neighbours(x, y) {
+		if w.get(w.cur, nb[0], nb[1]).Kind() == kind && (kind != Empty || !w.rocks.has(nb[0], nb[1])) {
+			out[n] = nb
+			n++
+		}
+	}
+	return n
+}
+
+// updateFish applies the Wa-Tor rules for a single fish at (x, y). It is
+// the Cell-based equivalent of World.updateFish.
+func (w *ChunkedWorld) updateFish(x, y int, c Cell, rng *rand.Rand) {
+	breed := c.Breed() + 1
+
+	var empties [4][2]int
+	n := w.neighboursOfKind(x, y, Empty, &empties)
+
+	// No free neighbours, or the chosen one is already taken in the new
+	// grid: the fish stays and does not reproduce.
+	var dest [2]int
+	if n > 0 {
+		dest = empties[rng.Intn(n)]
+	}
+	if n == 0 || w.get(w.next, dest[0], dest[1]) != 0 {
+		if w.get(w.next, x, y) == 0 {
+			w.set(w.next, x, y, makeCell(FishCell, breed, 0))
+		}
+		return
+	}
+
+	if breed >= w.Params.FishBreed {
+		if w.get(w.next, x, y) == 0 {
+			w.set(w.next, x, y, makeCell(FishCell, 0, 0))
+		}
+		w.set(w.next, dest[0], dest[1], makeCell(FishCell, 0, 0))
+	} else {
+		w.set(w.next, dest[0], dest[1], makeCell(FishCell, breed, 0))
+	}
+}
+
+// updateShark applies the Wa-Tor rules for a single shark at (x, y). It is
+// the Cell-based equivalent of World.updateShark.
+func (w *ChunkedWorld) updateShark(x, y int, c Cell, rng *rand.Rand) {
+	energy := c.Energy() - 1
+	if energy <= 0 {
+		return
+	}
+
+	breed := c.Breed() + 1
+	destX, destY := x, y
+	ate := false
+
+	var cands [4][2]int
+	if n := w.neighboursOfKind(x, y, FishCell, &cands); n > 0 {
+		d := cands[rng.Intn(n)]
+		destX, destY = d[0], d[1]
+		ate = true
+	} else if n := w.neighboursOfKind(x, y, Empty, &cands); n > 0 {
+		d := cands[rng.Intn(n)]
+		destX, destY = d[0], d[1]
+	}
+
+	if ate {
+		energy = w.Params.Starve
+		w.predation.add(destX, destY)
+	}
+
+	moved := destX != x || destY != y
+	if !ate && moved && w.get(w.next, destX, destY) != 0 {
+		destX, destY = x, y
+		moved = false
+	}
+
+	if breed >= w.Params.SharkBreed && moved {
+		if w.get(w.next, x, y) == 0 {
+			w.set(w.next, x, y, makeCell(SharkCell, 0, w.Params.Starve))
+		}
+		breed = 0
+	}
+
+	w.set(w.next, destX, destY, makeCell(SharkCell, breed, energy))
+}
@@ -0,0 +1,139 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+import (
+	"flag"
+	"testing"
+)
+
+// defaultParams returns the parameters the command line starts from.
+func defaultParams() Params {
+	var p Params
+	registerParamFlags(flag.NewFlagSet("test", flag.PanicOnError), &p)
+	return p
+}
+
+// TestBandPasses checks that no pass holds two chunk rows that can touch
+// the same cells, and that every row is updated exactly once.
+func TestBandPasses(t *testing.T) {
+	for _, size := range []int{257, 512, 513, 769, 1000, 1024, 1025, 1281, 1536, 1537} {
+		w := &ChunkedWorld{Size: size, chunksPerSide: (size + chunkSize - 1) / chunkSize}
+		seen := map[int]int{}
+		for _, pass := range w.bandPasses() {
+			for i, a := range pass {
+				seen[a]++
+				for _, b := range pass[i+1:] {
+					if reaches(w, a, b) {
+						t.Errorf("size %d: rows %d and %d share a pass but touch the same cells", size, a, b)
+					}
+				}
+			}
+		}
+		for cy := 0; cy < w.chunksPerSide; cy++ {
+			if seen[cy] != 1 {
+				t.Errorf("size %d: row %d is in %d passes", size, cy, seen[cy])
+			}
+		}
+	}
+}
+
+// reaches reports whether creatures of chunk rows a and b can touch a
+// common cell, by comparing the lines of cells each row reaches: its own
+// and the one above and below, wrapped around the torus.
+func reaches(w *ChunkedWorld, a, b int) bool {
+	lines := func(cy int) map[int]bool {
+		m := map[int]bool{}
+		for y := cy*chunkSize - 1; y <= min((cy+1)*chunkSize, w.Size); y++ {
+			m[(y+w.Size)%w.Size] = true
+		}
+		return m
+	}
+	la := lines(a)
+	for y := range lines(b) {
+		if la[y] {
+			return true
+		}
+	}
+	return false
+}
+
+// TestStepParallelRace runs the parallel step on grids whose last chunk
+// row is a single cell tall. Run it with -race: the rows on either side
+// of that line of cells used to be updated at the same time.
+func TestStepParallelRace(t *testing.T) {
+	for _, size := range []int{769, 1025} {
+		p := defaultParams()
+		p.GridSize, p.NumFish, p.NumShark, p.Seed = size, 100000, 20000, 1
+		p.Threads = 4
+		w, err := NewChunkedWorld(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 20; i++ {
+			w.StepParallel(p.Threads)
+		}
+		fish, sharks := w.Count()
+		if fish+sharks == 0 || fish+sharks > size*size {
+			t.Errorf("size %d: %d fish and %d sharks after 20 steps", size, fish, sharks)
+		}
+	}
+}
+
+// TestMakeCell checks that a Cell gives back what was packed into it and
+// that counters beyond maxCellCounter saturate instead of corrupting the
+// other fields.
+func TestMakeCell(t *testing.T) {
+	tests := []struct {
+		kind          CellType
+		breed, energy int
+		wantBreed     int
+		wantEnergy    int
+	}{
+		{Empty, 0, 0, 0, 0},
+		{FishCell, 0, 0, 0, 0},
+		{FishCell, 5, 0, 5, 0},
+		{SharkCell, 3, 7, 3, 7},
+		{SharkCell, maxCellCounter, maxCellCounter, maxCellCounter, maxCellCounter},
+		{FishCell, maxCellCounter + 1, 0, maxCellCounter, 0},
+		{SharkCell, 1000, 1, maxCellCounter, 1},
+		{SharkCell, 0, maxCellCounter + 1, 0, maxCellCounter},
+		{RockCell, 0, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		c := makeCell(tt.kind, tt.breed, tt.energy)
+		if c.Kind() != tt.kind || c.Breed() != tt.wantBreed || c.Energy() != tt.wantEnergy {
+			t.Errorf("makeCell(%v, %d, %d) = kind %v, breed %d, energy %d; want %v, %d, %d",
+				tt.kind, tt.breed, tt.energy, c.Kind(), c.Breed(), c.Energy(),
+				tt.kind, tt.wantBreed, tt.wantEnergy)
+		}
+	}
+	if makeCell(Empty, 0, 0) != 0 {
+		t.Error("an empty cell is not the zero Cell")
+	}
+}
+
+// TestChunkedMatchesLimits checks that a chunked world accepts the largest
+// times a Cell can count to and that its sharks start with full energy.
+func TestChunkedMatchesLimits(t *testing.T) {
+	p := Params{GridSize: 300, NumFish: 500, NumShark: 200, Seed: 7, Chunked: true,
+		FishBreed: maxCellCounter, SharkBreed: maxCellCounter, Starve: maxCellCounter}
+	w, err := NewChunkedWorld(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for y := 0; y < p.GridSize; y++ {
+		for x := 0; x < p.GridSize; x++ {
+			if info := w.Info(x, y); info.Kind == SharkCell && info.Energy != maxCellCounter {
+				t.Fatalf("shark at (%d, %d) starts with energy %d, want %d", x, y, info.Energy, maxCellCounter)
+			}
+		}
+	}
+	p.Starve = maxCellCounter + 1
+	if _, err := NewChunkedWorld(p); err == nil {
+		t.Errorf("starve = %d accepted by a chunked world", p.Starve)
+	}
+}
@@ -0,0 +1,78 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// compareParams are the parameters that may differ between the worlds of
+// -compare. Everything else, in particular the grid, the populations and
+// the seed, is shared so that every world starts from the same layout.
+var compareParams = []string{"fishBreed", "sharkBreed", "starve", "threads"}
+
+// maxCompare is the largest number of worlds -compare shows at once.
+const maxCompare = 9
+
+// compareSet is one world of a -compare run.
+type compareSet struct {
+	label  string // the overrides as given, e.g. "starve=5" ("base" if none)
+	params Params
+}
+
+// parseCompare splits spec, parameter sets separated by ";" of
+// comma-separated name=value pairs (e.g. "starve=3;starve=5,sharkBreed=8"),
+// and applies each set on top of base. An empty set is base itself.
+func parseCompare(base Params, spec string) ([]compareSet, error) {
+	parts := strings.Split(spec, ";")
+	if len(parts) < 2 || len(parts) > maxCompare {
+		return nil, fmt.Errorf("need 2 to %d parameter sets separated by ';' (got %d)", maxCompare, len(parts))
+	}
+
+	sets := make([]compareSet, 0, len(parts))
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		// Registering the flags sets q to the defaults; then q takes the
+		// values of base and the flags write the overrides into it.
+		fs := flag.NewFlagSet("compare", flag.ContinueOnError)
+		var q Params
+		registerParamFlags(fs, &q)
+		q = base
+		q.Compare = ""
+		if part != "" {
+			for _, kv := range strings.Split(part, ",") {
+				name, value, ok := strings.Cut(strings.TrimSpace(kv), "=")
+				if !ok {
+					return nil, fmt.Errorf("bad setting %q in %q (want name=value)", kv, part)
+				}
+				if !slices.Contains(compareParams, name) {
+					return nil, fmt.Errorf("%s cannot differ between the worlds (only %s can)",
+						name, strings.Join(compareParams, ", "))
+				}
+				if err := fs.Set(name, value); err != nil {
+					return nil, fmt.Errorf("parameter %s: %w", name, err)
+				}
+			}
+		}
+		label := part
+		if label == "" {
+			label = "base"
+		}
+		sets = append(sets, compareSet{label, q})
+	}
+	return sets, nil
+}
+
+// comparePath returns the statistics file of world i of a comparison:
+// path with "-1", "-2", ... inserted before its extension.
+func comparePath(path string, i int) string {
+	ext := filepath.Ext(path)
+	return fmt.Sprintf("%s-%d%s", strings.TrimSuffix(path, ext), i+1, ext)
+}
@@ -0,0 +1,194 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// configFlags are the options that read or print a configuration file.
+// They are accepted wherever the Params flags are.
+type configFlags struct {
+	path string // -config: file to take parameter values from
+	dump bool   // -dumpConfig: print the effective parameters and exit
+}
+
+// registerConfigFlags defines -config and -dumpConfig on fs.
+func registerConfigFlags(fs *flag.FlagSet) *configFlags {
+	c := &configFlags{}
+	fs.StringVar(&c.path, "config", "", "Read parameters from a JSON (.json) or TOML (.toml) file; flags override it")
+	fs.BoolVar(&c.dump, "dumpConfig", false, "Print the effective parameters as JSON and exit")
+	return c
+}
+
+// apply sets the parameter flags of fs (after fs.Parse) from the -config
+// file. Flags given on the command line keep their values, so a file can
+// hold an experiment and the command line vary it. If -dumpConfig is set,
+// the resulting parameters are printed and the program exits.
+func (c *configFlags) apply(fs *flag.FlagSet, p *Params) {
+	if c.path != "" {
+		if err := applyConfig(fs, c.path); err != nil {
+			fmt.Println("Error reading config file:", err)
+			os.Exit(1)
+		}
+	}
+	if c.dump {
+		if err := writeConfig(os.Stdout, *p); err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+}
+
+// applyConfig loads the configuration file at path and sets every flag of
+// fs named in it that was not set on the command line.
+func applyConfig(fs *flag.FlagSet, path string) error {
+	values, err := loadConfig(path)
+	if err != nil {
+		return err
+	}
+
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	paramFlags := flag.NewFlagSet("params", flag.ContinueOnError)
+	registerParamFlags(paramFlags, &Params{})
+	for name, v := range values {
+		if paramFlags.Lookup(name) == nil {
+			return fmt.Errorf("%s: unknown parameter %q", path, name)
+		}
+		if set[name] {
+			continue
+		}
+		if err := fs.Set(name, v); err != nil {
+			return fmt.Errorf("%s: parameter %s: %w", path, name, err)
+		}
+	}
+	return nil
+}
+
+// loadConfig reads a configuration file into flag values keyed by the
+// command-line flag names, e.g. {"starve": "5"}. The format is chosen by
+// the file extension: .toml files are TOML, anything else is JSON.
+func loadConfig(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		values, err := parseTOML(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return values, nil
+	}
+
+	var raw map[string]any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber() // keep large seeds exact
+	if err := dec.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	values := map[string]string{}
+	for name, v := range raw {
+		switch v := v.(type) {
+		case string:
+			values[name] = v
+		case json.Number:
+			values[name] = v.String()
+		case bool:
+			values[name] = strconv.FormatBool(v)
+		default:
+			return nil, fmt.Errorf("%s: parameter %s must be a string, number or boolean", path, name)
+		}
+	}
+	return values, nil
+}
+
+// parseTOML parses the flat subset of TOML that a Params file needs:
+// "key = value" lines with quoted strings, numbers and booleans, blank
+// lines and # comments. Tables and arrays are not supported.
+func parseTOML(data []byte) (map[string]string, error) {
+	values := map[string]string{}
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(text, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || key == "" || strings.HasPrefix(text, "[") {
+			return nil, fmt.Errorf("line %d: expected key = value", line)
+		}
+		key = strings.Trim(key, `"`)
+
+		if strings.HasPrefix(value, `"`) {
+			s, rest, err := cutTOMLString(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			if rest != "" && !strings.HasPrefix(rest, "#") {
+				return nil, fmt.Errorf("line %d: unexpected %q after string", line, rest)
+			}
+			values[key] = s
+			continue
+		}
+		if i := strings.Index(value, "#"); i >= 0 {
+			value = strings.TrimSpace(value[:i])
+		}
+		if value == "" {
+			return nil, fmt.Errorf("line %d: missing value for %s", line, key)
+		}
+		values[key] = strings.ReplaceAll(value, "_", "") // 1_000 = 1000
+	}
+	return values, sc.Err()
+}
+
+// cutTOMLString splits a basic (double-quoted) TOML string off the start of
+// s and returns its value and the trimmed remainder of s.
+func cutTOMLString(s string) (value, rest string, err error) {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			value, err = strconv.Unquote(s[:i+1])
+			return value, strings.TrimSpace(s[i+1:]), err
+		}
+	}
+	return "", "", fmt.Errorf("unterminated string %s", s)
+}
+
+// writeConfig writes p as a JSON object keyed by the flag names, which
+// can be read back with -config.
+func writeConfig(w io.Writer, p Params) error {
+	var cfg Params
+	fs := flag.NewFlagSet("params", flag.ContinueOnError)
+	registerParamFlags(fs, &cfg) // sets the defaults, so fill in p afterwards
+	cfg = p
+
+	values := map[string]any{}
+	fs.VisitAll(func(f *flag.Flag) {
+		values[f.Name] = f.Value.(flag.Getter).Get()
+	})
+	b, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", b)
+	return err
+}
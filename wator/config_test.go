@@ -0,0 +1,174 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFile writes content to name in a temporary directory and returns
+// its path.
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestParseTOML checks the supported subset of TOML and its errors.
+func TestParseTOML(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    map[string]string
+		wantErr string // part of the error message ("" = no error)
+	}{
+		{"empty", "", map[string]string{}, ""},
+		{"comments and blank lines", "# experiment\n\n  # indented\n", map[string]string{}, ""},
+		{"numbers and booleans", "gridSize = 50\nchunked = true\nstationaryTol = 0.05\n",
+			map[string]string{"gridSize": "50", "chunked": "true", "stationaryTol": "0.05"}, ""},
+		{"underscores in numbers", "seed = 1_000_000", map[string]string{"seed": "1000000"}, ""},
+		{"trailing comment", "starve = 3 # chronons", map[string]string{"starve": "3"}, ""},
+		{"string", `csv = "out/stats.csv"`, map[string]string{"csv": "out/stats.csv"}, ""},
+		{"string with # and escapes", `compare = "starve=2;\"x\" # not a comment" # comment`,
+			map[string]string{"compare": `starve=2;"x" # not a comment`}, ""},
+		{"quoted key", `"numFish" = 10`, map[string]string{"numFish": "10"}, ""},
+		{"table", "[world]\nsize = 3", nil, "line 1: expected key = value"},
+		{"no value", "starve =", nil, "line 1: missing value for starve"},
+		{"no equals", "starve 3", nil, "line 1: expected key = value"},
+		{"unterminated string", `csv = "stats.csv`, nil, "unterminated string"},
+		{"text after string", `csv = "a" b`, nil, `unexpected "b" after string`},
+	}
+	for _, tt := range tests {
+		got, err := parseTOML([]byte(tt.input))
+		switch {
+		case tt.wantErr != "":
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: error %v, want %q", tt.name, err, tt.wantErr)
+			}
+		case err != nil:
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		case !maps.Equal(got, tt.want):
+			t.Errorf("%s: parseTOML = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestLoadConfigJSON checks the value types a JSON file may hold.
+func TestLoadConfigJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    map[string]string
+		wantErr string
+	}{
+		{"types", `{"gridSize": 50, "csv": "a.csv", "graphics": true, "stationaryTol": 0.5}`,
+			map[string]string{"gridSize": "50", "csv": "a.csv", "graphics": "true", "stationaryTol": "0.5"}, ""},
+		{"large seed stays exact", `{"seed": 1732111391028112001}`,
+			map[string]string{"seed": "1732111391028112001"}, ""},
+		{"nested object", `{"world": {"size": 3}}`, nil, "must be a string, number or boolean"},
+		{"array", `{"threads": [1, 2]}`, nil, "must be a string, number or boolean"},
+		{"not JSON", `gridSize = 5`, nil, "invalid character"},
+	}
+	for _, tt := range tests {
+		got, err := loadConfig(writeFile(t, "config.json", tt.input))
+		switch {
+		case tt.wantErr != "":
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: error %v, want %q", tt.name, err, tt.wantErr)
+			}
+		case err != nil:
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		case !maps.Equal(got, tt.want):
+			t.Errorf("%s: loadConfig = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// parseWithConfig parses args like the main program does and applies the
+// -config file named in them.
+func parseWithConfig(t *testing.T, args ...string) (Params, error) {
+	t.Helper()
+	var p Params
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	registerParamFlags(fs, &p)
+	config := registerConfigFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	if config.path == "" {
+		return p, nil
+	}
+	return p, applyConfig(fs, config.path)
+}
+
+// TestConfigOverride checks the order of precedence: flags given on the
+// command line, then the file, then the defaults.
+func TestConfigOverride(t *testing.T) {
+	path := writeFile(t, "exp.toml", "gridSize = 50\nstarve = 4\nnumFish = 600\n")
+	defaults := defaultParams()
+
+	tests := []struct {
+		name                     string
+		args                     []string
+		gridSize, starve, sharks int
+	}{
+		{"file only", []string{"-config", path}, 50, 4, defaults.NumShark},
+		{"flag before -config", []string{"-starve=9", "-config", path}, 50, 9, defaults.NumShark},
+		{"flag after -config", []string{"-config", path, "-gridSize", "80", "-numShark=7"}, 80, 4, 7},
+		{"flag equal to the default", []string{"-config", path, "-starve=3"}, 50, 3, defaults.NumShark},
+	}
+	for _, tt := range tests {
+		p, err := parseWithConfig(t, tt.args...)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if p.GridSize != tt.gridSize || p.Starve != tt.starve || p.NumShark != tt.sharks || p.NumFish != 600 {
+			t.Errorf("%s: gridSize %d, starve %d, numShark %d, numFish %d; want %d, %d, %d, 600",
+				tt.name, p.GridSize, p.Starve, p.NumShark, p.NumFish, tt.gridSize, tt.starve, tt.sharks)
+		}
+	}
+
+	for _, bad := range []struct{ content, wantErr string }{
+		{"gridsize = 50", `unknown parameter "gridsize"`},
+		{"config = \"other.toml\"", `unknown parameter "config"`},
+		{"starve = many", "parameter starve"},
+	} {
+		_, err := parseWithConfig(t, "-config", writeFile(t, "bad.toml", bad.content))
+		if err == nil || !strings.Contains(err.Error(), bad.wantErr) {
+			t.Errorf("%q: error %v, want %q", bad.content, err, bad.wantErr)
+		}
+	}
+}
+
+// TestWriteConfigRoundTrip checks that -dumpConfig output read back with
+// -config gives the same parameters.
+func TestWriteConfigRoundTrip(t *testing.T) {
+	want := defaultParams()
+	want.GridSize, want.Seed, want.Chunked = 300, 1732111391028112001, true
+	want.CSVFile, want.StationaryTol, want.Compare = "run.csv", 0.125, "starve=2;starve=4"
+
+	var buf bytes.Buffer
+	if err := writeConfig(&buf, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := parseWithConfig(t, "-config", writeFile(t, "dump.json", buf.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("read back %+v\nwant %+v", got, want)
+	}
+}
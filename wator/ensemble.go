@@ -0,0 +1,205 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"runtime"
+	"slices"
+	"time"
+)
+
+// ensemblePercentiles are the percentile bands written for each species.
+var ensemblePercentiles = []float64{5, 25, 50, 75, 95}
+
+// Band summarises the values of one quantity across all replicates at a
+// single step.
+type Band struct {
+	Mean        float64   `json:"mean"`
+	StdDev      float64   `json:"sd"`
+	Percentiles []float64 `json:"percentiles"` // one value per entry of ensemblePercentiles
+}
+
+// newBand computes the mean, sample standard deviation and percentiles of
+// values. It sorts values in place.
+func newBand(values []float64) Band {
+	b := Band{Mean: mean(values)}
+	if len(values) > 1 {
+		sum := 0.0
+		for _, v := range values {
+			sum += (v - b.Mean) * (v - b.Mean)
+		}
+		b.StdDev = math.Sqrt(sum / float64(len(values)-1))
+	}
+
+	slices.Sort(values)
+	for _, q := range ensemblePercentiles {
+		b.Percentiles = append(b.Percentiles, percentile(values, q))
+	}
+	return b
+}
+
+// percentile returns the q-th percentile (0..100) of sorted values using
+// linear interpolation between the closest ranks.
+func percentile(sorted []float64, q float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	pos := q / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(pos))
+	hi := min(lo+1, len(sorted)-1)
+	return sorted[lo] + (pos-float64(lo))*(sorted[hi]-sorted[lo])
+}
+
+// replicate is one run of an ensemble: its populations at every step it
+// reached and how it ended.
+type replicate struct {
+	fish, sharks []float64
+	res          RunResult
+}
+
+// RunEnsemble runs p.Replicates copies of the simulation with consecutive
+// seeds starting at p.Seed, concurrently on one worker per CPU, and writes
+// the per-step mean, standard deviation and percentile bands of the fish
+// and shark populations to p.CSVFile in the format of p.StatsFormat. A run ended early by -stopOn leaves
+// the statistics of the later steps, which cover only the runs still going.
+func RunEnsemble(p Params) {
+	if p.CSVFile == "" {
+		fmt.Println("Error: -replicates needs -csv for the ensemble statistics")
+		os.Exit(1)
+	}
+
+	n := p.Replicates
+	runs := make([]replicate, n)
+	errs := make([]error, n)
+
+	start := time.Now()
+	runPool(n, min(n, runtime.NumCPU()), func(r int) {
+		rp := p.headless()
+		rp.Seed = p.Seed + int64(r)
+
+		world, err := newOcean(rp)
+		if err != nil {
+			errs[r] = err
+			return
+		}
+		run := &runs[r]
+		run.fish = make([]float64, 0, p.Steps)
+		run.sharks = make([]float64, 0, p.Steps)
+		run.res = simulate(world, rp, func(step, f, s int) {
+			run.fish = append(run.fish, float64(f))
+			run.sharks = append(run.sharks, float64(s))
+		})
+	})
+	elapsed := time.Since(start)
+	if err := errors.Join(errs...); err != nil {
+		fmt.Println("Error creating world:", err)
+		os.Exit(1)
+	}
+
+	m := newMetadata(p)
+	var fish, sharks []float64
+	stopped := 0
+	for r, run := range runs {
+		m.Replicates = append(m.Replicates, ReplicateInfo{
+			Seed:       p.Seed + int64(r),
+			StopStep:   run.res.Steps,
+			StopReason: run.res.StopReason,
+		})
+		fish = append(fish, float64(run.res.Fish))
+		sharks = append(sharks, float64(run.res.Sharks))
+		if run.res.StopReason != "" {
+			stopped++
+		}
+	}
+
+	if err := writeEnsemble(p.CSVFile, p.StatsFormat, m, runs); err != nil {
+		fmt.Println("Error writing CSV file:", err)
+		os.Exit(1)
+	}
+
+	f, s := newBand(fish), newBand(sharks)
+	fmt.Printf("\nEnsemble of %d runs finished in %v\n", n, elapsed)
+	if stopped > 0 {
+		fmt.Printf("Stopped early: %d of %d runs\n", stopped, n)
+	}
+	fmt.Printf("Final fish   : %.1f ± %.1f\n", f.Mean, f.StdDev)
+	fmt.Printf("Final sharks : %.1f ± %.1f\n", s.Mean, s.StdDev)
+}
+
+// live returns the populations at step of the runs that reached it.
+func live(runs []replicate, step int) (fish, sharks []float64) {
+	for _, run := range runs {
+		if step < len(run.fish) {
+			fish = append(fish, run.fish[step])
+			sharks = append(sharks, run.sharks[step])
+		}
+	}
+	return fish, sharks
+}
+
+// ensembleRow is one step of an ensemble statistics file in JSON Lines.
+type ensembleRow struct {
+	Step   int  `json:"step"`
+	N      int  `json:"n"`
+	Fish   Band `json:"fish"`
+	Sharks Band `json:"sharks"`
+}
+
+// writeEnsemble writes the ensemble statistics file in format, FormatCSV
+// or FormatJSONL. It starts with the run metadata, as comments or a
+// {"meta": ...} object, followed by one row per step with the number of
+// runs that reached it and the band statistics of both species over those
+// runs: step,n,fish_mean,fish_sd,fish_p5,...,sharks_p95 in CSV or an
+// ensembleRow object. The rows end at the last step any run reached.
+func writeEnsemble(path, format string, m Metadata, runs []replicate) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	sw := &StatsWriter{w: bufio.NewWriter(f), file: f, format: format}
+	w := sw.w
+	if format == FormatJSONL {
+		sw.writeJSON(jsonMeta{m})
+	} else {
+		writeCSVMetadata(w, m)
+		fmt.Fprint(w, "step,n")
+		for _, species := range []string{"fish", "sharks"} {
+			fmt.Fprintf(w, ",%s_mean,%s_sd", species, species)
+			for _, q := range ensemblePercentiles {
+				fmt.Fprintf(w, ",%s_p%g", species, q)
+			}
+		}
+		fmt.Fprintln(w)
+	}
+
+	for step := 0; ; step++ {
+		fish, sharks := live(runs, step)
+		if len(fish) == 0 {
+			break
+		}
+		row := ensembleRow{step, len(fish), newBand(fish), newBand(sharks)}
+		if format == FormatJSONL {
+			sw.writeJSON(row)
+			continue
+		}
+		fmt.Fprintf(w, "%d,%d", step, row.N)
+		for _, b := range []Band{row.Fish, row.Sharks} {
+			fmt.Fprintf(w, ",%.3f,%.3f", b.Mean, b.StdDev)
+			for _, v := range b.Percentiles {
+				fmt.Fprintf(w, ",%g", v)
+			}
+		}
+		fmt.Fprintln(w)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,146 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// ensembleFile is an ensemble CSV as written by writeEnsemble.
+type ensembleFile struct {
+	replicates []string   // "seed=... stop_step=... stop_reason=..."
+	rows       [][]string // the data rows, split at the commas
+}
+
+// runEnsemble runs p with RunEnsemble and reads back its CSV file.
+func runEnsemble(t *testing.T, p Params) ensembleFile {
+	t.Helper()
+	p.CSVFile = filepath.Join(t.TempDir(), "ensemble.csv")
+	RunEnsemble(p)
+
+	data, err := os.ReadFile(p.CSVFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var e ensembleFile
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	for i, line := range lines {
+		if _, info, ok := strings.Cut(line, "# replicate "); ok {
+			e.replicates = append(e.replicates, info[strings.Index(info, " ")+1:])
+		} else if !strings.HasPrefix(line, "#") {
+			if !strings.HasPrefix(line, "step,n,fish_mean,") {
+				t.Fatalf("line %d: header %q", i+1, line)
+			}
+			for _, row := range lines[i+1:] {
+				e.rows = append(e.rows, strings.Split(row, ","))
+			}
+			break
+		}
+	}
+	return e
+}
+
+// TestEnsembleStopOn checks that runs ended by -stopOn leave the
+// statistics instead of repeating their last populations, and that the
+// metadata says when and why each one stopped.
+func TestEnsembleStopOn(t *testing.T) {
+	p := defaultParams()
+	p.GridSize, p.NumFish, p.NumShark, p.Starve = 6, 2, 20, 2
+	p.Steps, p.Replicates, p.Seed = 100, 4, 1
+
+	full := runEnsemble(t, p)
+	if len(full.rows) != p.Steps {
+		t.Fatalf("without -stopOn: %d rows, want %d", len(full.rows), p.Steps)
+	}
+	for _, row := range full.rows {
+		if row[1] != "4" {
+			t.Fatalf("without -stopOn: step %s has n = %s, want 4", row[0], row[1])
+		}
+	}
+
+	// With these seeds the sharks of runs 1 and 2 die out at step 2,
+	// while those of runs 0 and 3 last the whole run.
+	p.StopOn = StopExtinct
+	stopped := runEnsemble(t, p)
+	want := []string{
+		"seed=1 stop_step=100 stop_reason=none",
+		"seed=2 stop_step=2 stop_reason=extinct",
+		"seed=3 stop_step=2 stop_reason=extinct",
+		"seed=4 stop_step=100 stop_reason=none",
+	}
+	if strings.Join(stopped.replicates, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("replicates %q, want %q", stopped.replicates, want)
+	}
+	if len(stopped.rows) != p.Steps {
+		t.Fatalf("%d rows, want %d", len(stopped.rows), p.Steps)
+	}
+	for step, row := range stopped.rows {
+		n := 4
+		if step > 2 {
+			n = 2
+		}
+		if got, _ := strconv.Atoi(row[1]); got != n {
+			t.Errorf("step %d: n = %d, want %d", step, got, n)
+		}
+		// While every run is going, they are the runs without -stopOn.
+		if n == 4 && strings.Join(row, ",") != strings.Join(full.rows[step], ",") {
+			t.Errorf("step %d: %v, want %v", step, row, full.rows[step])
+		}
+	}
+}
+
+// TestEnsembleJSONL checks that -statsFormat=jsonl writes the same
+// statistics and metadata as the CSV file.
+func TestEnsembleJSONL(t *testing.T) {
+	p := defaultParams()
+	p.GridSize, p.NumFish, p.NumShark, p.Starve = 6, 2, 20, 2
+	p.Steps, p.Replicates, p.Seed, p.StopOn = 100, 4, 1, StopExtinct
+	csv := runEnsemble(t, p)
+
+	p.StatsFormat = FormatJSONL
+	p.CSVFile = filepath.Join(t.TempDir(), "ensemble.jsonl")
+	RunEnsemble(p)
+	f, err := os.Open(p.CSVFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	if !sc.Scan() {
+		t.Fatal("empty file")
+	}
+	var meta jsonMeta
+	if err := json.Unmarshal(sc.Bytes(), &meta); err != nil {
+		t.Fatal(err)
+	}
+	if len(meta.Meta.Replicates) != 4 || meta.Meta.Replicates[1] != (ReplicateInfo{2, 2, StopExtinct}) {
+		t.Errorf("replicates %+v", meta.Meta.Replicates)
+	}
+
+	step := 0
+	for ; sc.Scan(); step++ {
+		var row ensembleRow
+		if err := json.Unmarshal(sc.Bytes(), &row); err != nil {
+			t.Fatalf("line %d: %v", step+2, err)
+		}
+		want := csv.rows[step]
+		got := []string{strconv.Itoa(row.Step), strconv.Itoa(row.N),
+			strconv.FormatFloat(row.Fish.Mean, 'f', 3, 64), strconv.FormatFloat(row.Fish.Percentiles[2], 'g', -1, 64)}
+		if got[0] != want[0] || got[1] != want[1] || got[2] != want[2] || got[3] != want[6] {
+			t.Errorf("step %d: step, n, fish mean and median %v, want %v", step, got, []string{want[0], want[1], want[2], want[6]})
+		}
+	}
+	if step != len(csv.rows) {
+		t.Errorf("%d rows, want %d", step, len(csv.rows))
+	}
+}
@@ -9,128 +9,247 @@ import (
 	"fmt"
 	"image/color"
 	"log"
+	"math"
+	"os"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
 	"golang.org/x/image/font/basicfont"
 )
 
 // Game wraps the Ebiten game state for the graphical Wa-Tor simulation.
-// It holds the current world, parameters, step counter and pre-created
-// images used to draw fish and sharks.
+// The world itself runs in a simulation on its own goroutine; the Game
+// handles input and draws the latest snapshot the simulation published.
+// With -compare the simulation runs several worlds, drawn side by side.
 type Game struct {
-	world    *World
-	params   Params
-	step     int // current simulation step
-	frame    int // frame counter used to slow down the simulation
-	fishImg  *ebiten.Image
-	sharkImg *ebiten.Image
+	params Params
+	sim    *simulation
+	snap   *snapshot // snapshot uploaded to imgs and shown by the overlays
+
+	paint   paintState   // mouse painting tool and brush
+	tuning  tuningPanel  // sliders changing the parameters, toggled with T
+	capture captureState // screenshots and recordings
+	help    bool         // show the key bindings
+	chart   bool         // show the population chart
+	view    int          // ViewNormal or an attribute view, cycled with V
+
+	// The simulation rasterises each world into display x display
+	// pixels, one per cell or, when it is wider than params.DisplaySize,
+	// per block of cells. Each new snapshot is uploaded to imgs, one per
+	// world, which are drawn through the same camera into tiles of
+	// viewW x viewH pixels, cols across, in a window of width x height.
+	// Zoomed in on a downsampled world, the cells in view are also
+	// uploaded one pixel each to details and drawn instead.
+	display   int
+	imgs      []*ebiten.Image
+	details   []*ebiten.Image
+	detailReq *region // cells last asked of the simulation (nil = none)
+	cam       camera
+	minimap   bool // show the minimap while zoomed in
+
+	width, height int
+	cols, rows    int
+	viewW, viewH  int
 }
 
-// pixelSize controls how many pixels wide and high each simulation cell is.
-const pixelSize = 4
-
-// windowScale controls how much larger the window is than the logical
-// resolution. Ebiten will scale the logical canvas up to this window size.
-const windowScale = 2
-
 // RunSimulationGraphics starts the Wa-Tor simulation using Ebiten for
 // graphical output. It opens a window and runs until the configured
 // number of steps has been reached or the user closes the window.
 func RunSimulationGraphics(p Params) {
-	world := NewWorld(p)
-
-	// Pre-create small images for fish and sharks to improve performance.
-	fishImg := ebiten.NewImage(pixelSize, pixelSize)
-	fishImg.Fill(color.RGBA{0, 200, 255, 255}) // cyan-ish fish
-
-	sharkImg := ebiten.NewImage(pixelSize, pixelSize)
-	sharkImg.Fill(color.RGBA{255, 100, 50, 255}) // orange-ish shark
-
 	g := &Game{
-		world:    world,
-		params:   p,
-		step:     0,
-		frame:    0,
-		fishImg:  fishImg,
-		sharkImg: sharkImg,
+		params:  p,
+		paint:   paintState{brush: 1},
+		display: p.GridSize,
+		minimap: true,
+	}
+	if p.DisplaySize > 0 && p.GridSize > p.DisplaySize {
+		g.display = p.DisplaySize
 	}
 
-	logicalW := p.GridSize * pixelSize
-	logicalH := p.GridSize * pixelSize
+	sets := []compareSet{{"", p}}
+	if p.Compare != "" {
+		var err error
+		if sets, err = parseCompare(p, p.Compare); err != nil {
+			log.Fatal(err) // already checked by Validate
+		}
+	}
+	g.tuning = newTuningPanel(sets)
+	g.capture = newCaptureState(p)
+	var err error
+	if g.sim, err = newSimulation(sets, g.display); err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	for range sets {
+		g.imgs = append(g.imgs, ebiten.NewImage(g.display, g.display))
+	}
+	g.details = make([]*ebiten.Image, len(sets))
+	g.cols = int(math.Ceil(math.Sqrt(float64(len(sets)))))
+	g.rows = (len(sets) + g.cols - 1) / g.cols
+
+	// Each tile starts at PixelSize*WindowScale pixels per display cell,
+	// but the window is no larger than 90% of the screen, and can be
+	// resized freely.
+	side := float64(g.display*p.PixelSize) * p.WindowScale
+	w, h := side*float64(g.cols), side*float64(g.rows)
+	if m := ebiten.Monitor(); m != nil {
+		if mw, mh := m.Size(); mw > 0 && mh > 0 {
+			f := math.Min(1, math.Min(0.9*float64(mw)/w, 0.9*float64(mh)/h))
+			w, h = w*f, h*f
+		}
+	}
+	g.Layout(int(w), int(h))
+	g.resetCamera()
 
-	ebiten.SetWindowSize(logicalW*windowScale, logicalH*windowScale)
+	ebiten.SetWindowSize(int(w), int(h))
+	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
 	ebiten.SetWindowTitle("Wa-Tor Simulation")
 
-	if err := ebiten.RunGame(g); err != nil {
-		log.Fatal(err)
+	g.sim.start()
+	runErr := ebiten.RunGame(g)
+	g.capture.finish()
+	if err := g.sim.stop(); err != nil {
+		fmt.Println("Error writing CSV file:", err)
+		os.Exit(1)
+	}
+	if runErr != nil {
+		log.Fatal(runErr)
 	}
 }
 
-// Layout reports the logical resolution of the game. Ebiten will scale
-// this resolution up to the actual window size.
+// Layout makes the logical resolution of the game the size of the
+// window, so resizing the window shows more or less of the world rather
+// than stretching it. The camera decides how large the cells are.
 func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
-	return g.params.GridSize * pixelSize, g.params.GridSize * pixelSize
+	g.width, g.height = outsideWidth, outsideHeight
+	g.viewW, g.viewH = max(1, g.width/g.cols), max(1, g.height/g.rows)
+	return outsideWidth, outsideHeight
 }
 
-// Update advances the simulation. It is called every frame by Ebiten.
-// To slow the simulation down, we only perform a simulation step every
-// few frames. When the configured number of steps is reached, the game
-// terminates.
-func (g *Game) Update() error {
-
-	if g.step >= g.params.Steps {
-		return ebiten.Termination
+// tileAt returns the tile under window pixel (x, y) and the pixel's
+// position within that tile.
+func (g *Game) tileAt(x, y int) (tile, tx, ty int, ok bool) {
+	col, row := x/g.viewW, y/g.viewH
+	tile = row*g.cols + col
+	if x < 0 || y < 0 || col >= g.cols || tile >= len(g.imgs) {
+		return 0, 0, 0, false
 	}
+	return tile, x - col*g.viewW, y - row*g.viewH, true
+}
 
-	g.frame++
-
-	const framesPerStep = 4
-	if g.frame%framesPerStep != 0 {
-		return nil
+// Update handles the keyboard and mouse. It is called every frame by
+// Ebiten; the simulation steps on its own goroutine at its target speed.
+// When the configured number of steps is reached, the game terminates.
+func (g *Game) Update() error {
+	if err := g.handleKeys(); err != nil {
+		return err
 	}
-
-	if g.params.Threads > 1 {
-		g.world.StepParallel(g.params.Threads)
+	g.handlePaintKeys()
+	g.handleCaptureKeys()
+	if g.handleTuning() || g.handleCamera() {
+		g.paint.drawing = false
 	} else {
-		g.world.Step()
+		g.handleMouse()
 	}
+	g.sendStrokes()
+	g.requestDetail()
 
-	g.step++
+	if g.sim.latest.Load().done {
+		return ebiten.Termination
+	}
 	return nil
 }
 
 // Draw renders the current world state to the Ebiten screen. Fish and
 // sharks are drawn in different colours on a dark “water” background,
-// and a simple HUD shows the step counter and population sizes.
+// and a simple HUD shows the step counter and population sizes. The
+// simulation has already rasterised the world into the snapshot, so a
+// frame is a single WritePixels when a new snapshot has arrived and one
+// draw through the camera. Screenshots and recordings read the screen
+// back before or after the overlays.
 func (g *Game) Draw(screen *ebiten.Image) {
-
-	water := color.RGBA{0, 10, 40, 255}
-	screen.Fill(water)
-
-	for y := 0; y < g.params.GridSize; y++ {
-		for x := 0; x < g.params.GridSize; x++ {
-			cell := g.world.CellAt(x, y)
-
-			var img *ebiten.Image
-			switch cell {
-			case FishCell:
-				img = g.fishImg
-			case SharkCell:
-				img = g.sharkImg
-			default:
-				continue
+	snap := g.sim.snapshot()
+	newSnap := snap != g.snap
+	if newSnap {
+		for i, w := range snap.worlds {
+			g.imgs[i].WritePixels(w.pixels)
+			if w.detail != nil {
+				g.uploadDetail(i, snap.detail, w.detail)
 			}
-
-			op := &ebiten.DrawImageOptions{}
-			op.GeoM.Translate(float64(x*pixelSize), float64(y*pixelSize))
-			screen.DrawImage(img, op)
 		}
+		if g.snap != nil {
+			g.sim.release(g.snap)
+		}
+		g.snap = snap
+	}
+	g.drawWorld(screen)
+	if !g.capture.hud {
+		g.grab(screen, newSnap)
+	}
+	g.drawOverlays(screen)
+	if g.capture.hud {
+		g.grab(screen, newSnap)
+	}
+	g.drawCaptureStatus(screen)
+}
+
+// drawOverlays draws the HUD, the legend of an attribute view, the
+// minimap and, if enabled, the tuning panel, chart and help box on top of
+// the world.
+func (g *Game) drawOverlays(screen *ebiten.Image) {
+	g.drawHUD(screen)
+	if len(g.imgs) > 1 {
+		g.drawTileLabels(screen)
 	}
+	if g.snap.view != ViewNormal {
+		g.drawLegend(screen)
+	}
+	g.drawMinimap(screen)
+	g.drawTuning(screen)
+	if g.chart {
+		g.drawChart(screen)
+	}
+	if g.help {
+		g.drawHelp(screen)
+	}
+}
 
-	fishCount, sharkCount := g.world.Count()
-	hud := fmt.Sprintf("Step: %d / %d   Fish: %d   Sharks: %d",
-		g.step, g.params.Steps, fishCount, sharkCount)
+// drawHUD draws the step counter, population sizes and speed at the top
+// of the screen. In a comparison the populations are in each tile's label.
+func (g *Game) drawHUD(screen *ebiten.Image) {
+	snap := g.snap
+	pop, speed := "", snap.speedString()
+	if len(snap.worlds) == 1 {
+		w := snap.worlds[0]
+		pop = fmt.Sprintf("   Fish: %d   Sharks: %d", w.fish, w.sharks)
+		if w.stopped != "" && snap.paused {
+			speed = "stopped: " + w.stopped + " (Space continues)"
+		}
+	}
+	hud := fmt.Sprintf("Step: %d / %d%s   %s   Brush: %s %d   H: help",
+		snap.step, g.params.Steps, pop, speed,
+		paintTools[g.paint.tool].name, g.paint.brush)
 
 	text.Draw(screen, hud, basicfont.Face7x13, 8, 16, color.White)
 }
+
+// drawTileLabels separates the tiles of a comparison and labels each at
+// its bottom-left corner with its parameter set, in its chart colour, and
+// its populations.
+func (g *Game) drawTileLabels(screen *ebiten.Image) {
+	for i, w := range g.snap.worlds {
+		x, y := (i%g.cols)*g.viewW, (i/g.cols)*g.viewH
+		vector.StrokeRect(screen, float32(x), float32(y), float32(g.viewW), float32(g.viewH), 2, chartAxis, false)
+
+		label := fmt.Sprintf("%s   Fish: %d   Sharks: %d", w.label, w.fish, w.sharks)
+		if w.stopped != "" {
+			label += "   Stopped: " + w.stopped
+		}
+		lw := text.BoundString(basicfont.Face7x13, label).Dx()
+		vector.FillRect(screen, float32(x+4), float32(y+g.viewH-24), float32(lw+20), 20, chartBack, false)
+		fish, _ := seriesColours(i, len(g.snap.worlds))
+		vector.FillRect(screen, float32(x+8), float32(y+g.viewH-18), 8, 8, fish, false)
+		text.Draw(screen, label, basicfont.Face7x13, x+20, y+g.viewH-9, color.White)
+	}
+}
@@ -0,0 +1,292 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Limits of the camera zoom, in window pixels per display cell, and how
+// much one notch of the mouse wheel zooms.
+const (
+	maxZoom  = 64
+	zoomStep = 1.15
+)
+
+// minimapSize is the side of the minimap in window pixels.
+const minimapSize = 160
+
+// camera is the part of the world shown in the window, or in each tile
+// of a comparison, which all show the same part of their worlds. The
+// world wraps around, so the camera can pan forever in any direction and
+// the window shows the torus tiled when it is zoomed out.
+type camera struct {
+	x, y    float64 // display cell at the centre of the window
+	zoom    float64 // window pixels per display cell
+	panning bool    // the middle button was held on the previous frame
+	lastX   int     // cursor position on the previous frame while panning
+	lastY   int
+}
+
+// zoomRange returns the smallest zoom, at which the whole world fits the
+// shorter side of a tile, and the largest.
+func (g *Game) zoomRange() (float64, float64) {
+	fit := float64(min(g.viewW, g.viewH)) / float64(g.display)
+	return min(fit, maxZoom), max(fit, maxZoom)
+}
+
+// resetCamera centres the world and zooms out until it fits the window.
+func (g *Game) resetCamera() {
+	d := float64(g.display)
+	g.cam.x, g.cam.y = d/2, d/2
+	g.cam.zoom, _ = g.zoomRange()
+}
+
+// origin returns the display cell, not wrapped, at the top-left corner
+// of a tile.
+func (g *Game) origin() (float64, float64) {
+	return g.cam.x - float64(g.viewW)/(2*g.cam.zoom), g.cam.y - float64(g.viewH)/(2*g.cam.zoom)
+}
+
+// screenToDisplay returns the display cell under pixel (mx, my) of a
+// tile, wrapped onto the torus.
+func (g *Game) screenToDisplay(mx, my int) (float64, float64) {
+	ox, oy := g.origin()
+	d := float64(g.display)
+	return wrapFloat(ox+float64(mx)/g.cam.zoom, d), wrapFloat(oy+float64(my)/g.cam.zoom, d)
+}
+
+// wrapFloat returns v modulo n in [0, n).
+func wrapFloat(v, n float64) float64 {
+	v = math.Mod(v, n)
+	if v < 0 {
+		v += n
+	}
+	return v
+}
+
+// pan moves the camera by dx, dy display cells.
+func (g *Game) pan(dx, dy float64) {
+	d := float64(g.display)
+	g.cam.x = wrapFloat(g.cam.x+dx, d)
+	g.cam.y = wrapFloat(g.cam.y+dy, d)
+}
+
+// handleCamera zooms with the mouse wheel around the cursor, pans while
+// the middle button is dragged or the arrow keys are held, fits the world
+// with F and toggles the minimap with M. A left click on the minimap
+// centres the camera there; handleCamera then returns true so the click
+// does not paint.
+func (g *Game) handleCamera() bool {
+	if inpututil.IsKeyJustPressed(ebiten.KeyF) {
+		g.resetCamera()
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyM) {
+		g.minimap = !g.minimap
+	}
+
+	lo, hi := g.zoomRange()
+	mx, my := ebiten.CursorPosition()
+	if _, dy := ebiten.Wheel(); dy != 0 {
+		// Keep the cell under the cursor where it is.
+		_, tx, ty, _ := g.tileAt(mx, my)
+		ox, oy := g.origin()
+		px, py := ox+float64(tx)/g.cam.zoom, oy+float64(ty)/g.cam.zoom
+		g.cam.zoom = math.Max(lo, math.Min(hi, g.cam.zoom*math.Pow(zoomStep, dy)))
+		g.cam.x = px - float64(tx)/g.cam.zoom + float64(g.viewW)/(2*g.cam.zoom)
+		g.cam.y = py - float64(ty)/g.cam.zoom + float64(g.viewH)/(2*g.cam.zoom)
+	}
+	g.cam.zoom = math.Max(lo, math.Min(hi, g.cam.zoom)) // the window may have been resized
+
+	if ebiten.IsMouseButtonPressed(ebiten.MouseButtonMiddle) {
+		if g.cam.panning {
+			g.pan(float64(g.cam.lastX-mx)/g.cam.zoom, float64(g.cam.lastY-my)/g.cam.zoom)
+		}
+		g.cam.panning = true
+		g.cam.lastX, g.cam.lastY = mx, my
+	} else {
+		g.cam.panning = false
+	}
+
+	step := float64(min(g.viewW, g.viewH)) / 60 / g.cam.zoom
+	for _, k := range []struct {
+		key    ebiten.Key
+		dx, dy float64
+	}{
+		{ebiten.KeyArrowLeft, -step, 0}, {ebiten.KeyArrowRight, step, 0},
+		{ebiten.KeyArrowUp, 0, -step}, {ebiten.KeyArrowDown, 0, step},
+	} {
+		if ebiten.IsKeyPressed(k.key) {
+			g.pan(k.dx, k.dy)
+		}
+	}
+	g.pan(0, 0) // wrap the centre after zooming
+
+	x, y, size, ok := g.minimapRect()
+	if !ok || !ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) ||
+		mx < x || my < y || mx >= x+size || my >= y+size {
+		return false
+	}
+	scale := float64(size) / float64(g.display)
+	g.cam.x, g.cam.y = float64(mx-x)/scale, float64(my-y)/scale
+	return true
+}
+
+// minimapRect returns where the minimap is drawn, in the top-right corner
+// of the window. ok is false when it is hidden: switched off with M, or
+// not needed because the whole world is in view.
+func (g *Game) minimapRect() (x, y, size int, ok bool) {
+	lo, _ := g.zoomRange()
+	size = min(minimapSize, g.viewW/3, g.viewH/3)
+	if !g.minimap || g.cam.zoom <= lo*1.001 || size < 16 {
+		return 0, 0, 0, false
+	}
+	return g.width - size - 8, 28, size, true
+}
+
+// visibleCells returns the world cell, wrapped onto the torus, at the
+// top-left corner of a tile and how many world cells the tile shows
+// across and down.
+func (g *Game) visibleCells() (x, y, w, h float64) {
+	n := float64(g.params.GridSize)
+	scale := n / float64(g.display) // world cells per display cell
+	ox, oy := g.origin()
+	return wrapFloat(ox*scale, n), wrapFloat(oy*scale, n),
+		float64(g.viewW) / g.cam.zoom * scale, float64(g.viewH) / g.cam.zoom * scale
+}
+
+// covers reports whether region r of a world of size n holds the w x h
+// cells from (x, y), and where they start within it.
+func (r region) covers(x, y, w, h float64, n int) (dx, dy float64, ok bool) {
+	if r.w == 0 {
+		return 0, 0, false
+	}
+	dx = wrapFloat(x-float64(r.x), float64(n))
+	dy = wrapFloat(y-float64(r.y), float64(n))
+	return dx, dy, dx+w <= float64(r.w) && dy+h <= float64(r.h)
+}
+
+// requestDetail asks the simulation for the cells in view at full
+// resolution once the camera is zoomed in on a downsampled world far
+// enough that every cell is at least a window pixel wide. The region
+// reaches a quarter of the view past each edge, so the camera can pan a
+// little before a new one is needed.
+func (g *Game) requestDetail() {
+	n := g.params.GridSize
+	x, y, w, h := g.visibleCells()
+	if g.display >= n || g.cam.zoom < float64(n)/float64(g.display) || w > float64(n) || h > float64(n) {
+		if g.detailReq != nil {
+			g.detailReq = nil
+			g.sim.requestDetail(nil)
+		}
+		return
+	}
+	if g.detailReq != nil {
+		if _, _, ok := g.detailReq.covers(x, y, w, h, n); ok {
+			return
+		}
+	}
+	mx, my := int(w/4)+1, int(h/4)+1
+	g.detailReq = &region{
+		x: (int(x) - mx + n) % n,
+		y: (int(y) - my + n) % n,
+		w: int(math.Ceil(w)) + 2*mx + 1,
+		h: int(math.Ceil(h)) + 2*my + 1,
+	}
+	g.sim.requestDetail(g.detailReq)
+}
+
+// uploadDetail copies pix, the cells of region r of world i, to the top-
+// left corner of its detail image, which grows when r does not fit.
+func (g *Game) uploadDetail(i int, r region, pix []byte) {
+	img := g.details[i]
+	if img == nil || img.Bounds().Dx() < r.w || img.Bounds().Dy() < r.h {
+		w, h := r.w, r.h
+		if img != nil {
+			w, h = max(w, img.Bounds().Dx()), max(h, img.Bounds().Dy())
+			img.Deallocate()
+		}
+		img = ebiten.NewImage(w, h)
+		g.details[i] = img
+	}
+	img.SubImage(image.Rect(0, 0, r.w, r.h)).(*ebiten.Image).WritePixels(pix)
+}
+
+// drawWorld draws the worlds of the latest snapshot through the camera,
+// each into its tile. The quad of a tile covers it entirely and its
+// texture coordinates run past the edges of the image, which
+// AddressRepeat wraps around like the torus. When the snapshot has the
+// cells in view at full resolution, those are drawn instead.
+func (g *Game) drawWorld(screen *ebiten.Image) {
+	ox, oy := g.origin()
+	d := float64(g.display)
+	ox, oy = wrapFloat(ox, d), wrapFloat(oy, d)
+	sx0, sy0 := float32(ox), float32(oy)
+	sx1, sy1 := float32(ox+float64(g.viewW)/g.cam.zoom), float32(oy+float64(g.viewH)/g.cam.zoom)
+
+	imgs := g.imgs
+	op := &ebiten.DrawTrianglesOptions{Address: ebiten.AddressRepeat}
+	if g.cam.zoom < 1 {
+		op.Filter = ebiten.FilterLinear
+	}
+	x, y, w, h := g.visibleCells()
+	if dx, dy, ok := g.snap.detail.covers(x, y, w, h, g.params.GridSize); ok && g.snap.worlds[0].detail != nil {
+		imgs = g.details
+		op = &ebiten.DrawTrianglesOptions{}
+		sx0, sy0 = float32(dx), float32(dy)
+		sx1, sy1 = float32(dx+w), float32(dy+h)
+	}
+	for i, img := range imgs {
+		x0, y0 := float32((i%g.cols)*g.viewW), float32((i/g.cols)*g.viewH)
+		x1, y1 := x0+float32(g.viewW), y0+float32(g.viewH)
+		vertices := []ebiten.Vertex{
+			{DstX: x0, DstY: y0, SrcX: sx0, SrcY: sy0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+			{DstX: x1, DstY: y0, SrcX: sx1, SrcY: sy0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+			{DstX: x0, DstY: y1, SrcX: sx0, SrcY: sy1, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+			{DstX: x1, DstY: y1, SrcX: sx1, SrcY: sy1, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
+		}
+		screen.DrawTriangles(vertices, []uint16{0, 1, 2, 1, 2, 3}, img, op)
+	}
+}
+
+// drawMinimap draws the whole (first) world in the corner of the window
+// with the part shown by the camera outlined. The outline wraps around the edges
+// of the minimap like the camera wraps around the torus.
+func (g *Game) drawMinimap(screen *ebiten.Image) {
+	x, y, size, ok := g.minimapRect()
+	if !ok {
+		return
+	}
+	scale := float64(size) / float64(g.display)
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(scale, scale)
+	op.GeoM.Translate(float64(x), float64(y))
+	if scale < 1 {
+		op.Filter = ebiten.FilterLinear
+	}
+	screen.DrawImage(g.imgs[0], op)
+
+	// Sub-images keep the coordinates of the screen but clip to the
+	// minimap, so the wrapped copies of the outline are cut at its edges.
+	mini := screen.SubImage(image.Rect(x, y, x+size, y+size)).(*ebiten.Image)
+	ox, oy := g.origin()
+	d := float64(g.display)
+	ox, oy = wrapFloat(ox, d), wrapFloat(oy, d)
+	vw, vh := float32(float64(g.viewW)/g.cam.zoom*scale), float32(float64(g.viewH)/g.cam.zoom*scale)
+	for _, dx := range []float64{0, -d} {
+		for _, dy := range []float64{0, -d} {
+			vector.StrokeRect(mini, float32(float64(x)+(ox+dx)*scale), float32(float64(y)+(oy+dy)*scale),
+				vw, vh, 1, color.White, false)
+		}
+	}
+	vector.StrokeRect(screen, float32(x)-1, float32(y)-1, float32(size)+2, float32(size)+2, 1, chartAxis, false)
+}
@@ -0,0 +1,188 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"sync"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"golang.org/x/image/font/basicfont"
+)
+
+// noticeTime is how long a capture message stays in the window.
+const noticeTime = 3 * time.Second
+
+// captureState holds screenshots and recordings of the window. P saves
+// the next frame as a PNG, G starts and stops recording every new frame
+// and O chooses whether captures include the HUD and other overlays.
+// Files are written on other goroutines; each reports where it went by a
+// notice, shown in the window and printed to the terminal.
+type captureState struct {
+	dir    string
+	format string
+	hud    bool // capture the overlays as well as the world
+	shot   bool // save the next frame
+
+	rec     *recorder      // nil when not recording
+	pending sync.WaitGroup // screenshots and recordings still being written
+
+	notices     chan string
+	notice      string
+	noticeUntil time.Time
+}
+
+// newCaptureState sets up captures as configured by the flags.
+func newCaptureState(p Params) captureState {
+	return captureState{
+		dir:     p.CaptureDir,
+		format:  p.RecordFormat,
+		hud:     p.CaptureHUD,
+		notices: make(chan string, 16),
+	}
+}
+
+// report prints msg and shows it in the window. It may be called from
+// any goroutine.
+func (c *captureState) report(msg string) {
+	fmt.Println(msg)
+	select {
+	case c.notices <- msg:
+	default:
+	}
+}
+
+// handleCaptureKeys applies P, G and O and picks up the notices of
+// captures that have finished.
+func (g *Game) handleCaptureKeys() {
+	c := &g.capture
+	if inpututil.IsKeyJustPressed(ebiten.KeyP) {
+		c.shot = true
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyG) {
+		if c.rec == nil {
+			rec, err := startRecording(c.dir, c.format)
+			if err != nil {
+				c.report(fmt.Sprint("Error starting recording: ", err))
+			} else {
+				c.rec = rec
+			}
+		} else {
+			c.stopRecording()
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyO) {
+		c.hud = !c.hud
+		state := "off"
+		if c.hud {
+			state = "on"
+		}
+		c.report("Overlays in captures: " + state)
+	}
+
+	for {
+		select {
+		case msg := <-c.notices:
+			c.notice, c.noticeUntil = msg, time.Now().Add(noticeTime)
+		default:
+			return
+		}
+	}
+}
+
+// stopRecording ends the current recording. The last frames are written
+// in the background; the notice says when the file is complete.
+func (c *captureState) stopRecording() {
+	rec := c.rec
+	c.rec = nil
+	c.pending.Add(1)
+	go func() {
+		defer c.pending.Done()
+		if err := rec.stop(); err != nil {
+			c.report(fmt.Sprint("Error writing recording: ", err))
+			return
+		}
+		c.report(fmt.Sprintf("Saved recording %s (%d frames)", rec.path, rec.count))
+	}()
+}
+
+// finish stops any recording and waits until every capture has been
+// written. It is called when the window closes.
+func (c *captureState) finish() {
+	if c.rec != nil {
+		c.stopRecording()
+	}
+	c.pending.Wait()
+}
+
+// grab captures screen if a screenshot was asked for or, while recording,
+// if it shows a new snapshot. Draw calls it before the overlays are
+// drawn, or after them when they are included.
+func (g *Game) grab(screen *ebiten.Image, newSnap bool) {
+	c := &g.capture
+	frame := c.rec != nil && newSnap
+	if !c.shot && !frame {
+		return
+	}
+	img := image.NewRGBA(screen.Bounds())
+	screen.ReadPixels(img.Pix)
+	for i := 3; i < len(img.Pix); i += 4 {
+		img.Pix[i] = 255 // parts of the window outside the tiles are transparent
+	}
+
+	if c.shot {
+		c.shot = false
+		path, err := newCapturePath(c.dir, "shot", ".png")
+		if err != nil {
+			c.report(fmt.Sprint("Error saving screenshot: ", err))
+		} else {
+			c.pending.Add(1)
+			go func() {
+				defer c.pending.Done()
+				if err := savePNG(path, img); err != nil {
+					c.report(fmt.Sprint("Error saving screenshot: ", err))
+					return
+				}
+				c.report("Saved screenshot " + path)
+			}()
+		}
+	}
+	if frame && !c.rec.add(img, time.Now()) {
+		c.report(fmt.Sprintf("Recording stopped: a GIF holds at most %d frames", gifMaxFrames))
+		c.stopRecording()
+	}
+}
+
+// drawCaptureStatus shows that a recording is running and the latest
+// notice in the top-right corner. It is drawn after the frame has been
+// captured, so it never appears in screenshots or recordings.
+func (g *Game) drawCaptureStatus(screen *ebiten.Image) {
+	c := &g.capture
+	var lines []string
+	if c.rec != nil {
+		lines = append(lines, fmt.Sprintf("REC %s  %d frames", c.format, c.rec.count))
+	}
+	if c.notice != "" && time.Now().Before(c.noticeUntil) {
+		lines = append(lines, c.notice)
+	}
+	y := 4
+	for _, line := range lines {
+		w := text.BoundString(basicfont.Face7x13, line).Dx()
+		x := g.width - w - 16
+		vector.FillRect(screen, float32(x-6), float32(y), float32(w+14), 18, color.RGBA{0, 0, 0, 200}, false)
+		if c.rec != nil && line == lines[0] {
+			vector.FillCircle(screen, float32(x-14), float32(y+9), 5, color.RGBA{230, 30, 30, 255}, true)
+		}
+		text.Draw(screen, line, basicfont.Face7x13, x+2, y+13, color.White)
+		y += 20
+	}
+}
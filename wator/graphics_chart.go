@@ -0,0 +1,134 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"golang.org/x/image/font/basicfont"
+)
+
+// chartHistory is the number of past steps plotted by the population chart.
+const chartHistory = 500
+
+// Colours of the population chart, matching the cells.
+var (
+	fishLine  = color.RGBA{0, 200, 255, 255}
+	sharkLine = color.RGBA{255, 100, 50, 255}
+	chartBack = color.RGBA{0, 0, 0, 190}
+	chartAxis = color.RGBA{90, 90, 110, 255}
+)
+
+// worldColours tell the worlds of a comparison apart in the chart.
+var worldColours = []color.RGBA{
+	{0, 200, 255, 255}, {255, 100, 50, 255}, {120, 230, 80, 255},
+	{240, 90, 220, 255}, {250, 220, 60, 255}, {150, 130, 255, 255},
+	{255, 160, 170, 255}, {60, 220, 180, 255}, {200, 200, 200, 255},
+}
+
+// seriesColours returns the chart colours of the fish and sharks of world
+// i out of n. A single world uses the colours of its cells; in a
+// comparison each world has its own colour, darker for the sharks.
+func seriesColours(i, n int) (fish, sharks color.RGBA) {
+	if n == 1 {
+		return fishLine, sharkLine
+	}
+	c := worldColours[i%len(worldColours)]
+	return c, color.RGBA{c.R / 2, c.G / 2, c.B / 2, 255}
+}
+
+// popSample is the population of the world at one step.
+type popSample struct {
+	fish, sharks int
+}
+
+// record adds the populations at step to the chart history, keeping the
+// last chartHistory steps, and to the statistics file if there is one.
+func (w *simWorld) record(step int) {
+	fish, sharks := w.ocean.Count()
+	if w.stats != nil {
+		w.stats.Row(step, fish, sharks)
+	}
+	if len(w.history) == chartHistory {
+		copy(w.history, w.history[1:])
+		w.history = w.history[:chartHistory-1]
+	}
+	w.history = append(w.history, popSample{fish, sharks})
+}
+
+// drawChart draws a panel along the bottom of the screen with the fish
+// and shark counts of the last chartHistory steps on the left and the
+// phase-space trail (fish against sharks) on the right, for every world
+// on common scales.
+func (g *Game) drawChart(screen *ebiten.Image) {
+	worlds := g.snap.worlds
+	if len(worlds[0].history) < 2 {
+		return
+	}
+	sw, sh := screen.Bounds().Dx(), screen.Bounds().Dy()
+	pw, ph := float32(sw-16), float32(max(80, sh/3))
+	px, py := float32(8), float32(sh)-ph-8
+	vector.FillRect(screen, px, py, pw, ph, chartBack, false)
+
+	maxFish, maxSharks := 1, 1
+	for _, w := range worlds {
+		for _, s := range w.history {
+			maxFish, maxSharks = max(maxFish, s.fish), max(maxSharks, s.sharks)
+		}
+	}
+	maxPop := max(maxFish, maxSharks)
+
+	// Time series: all populations on one scale, newest on the right.
+	const pad = 6
+	tx, ty := px+pad, py+pad+14
+	tw, th := pw*2/3-2*pad, ph-2*pad-14
+	vector.StrokeRect(screen, tx, ty, tw, th, 1, chartAxis, false)
+	point := func(i, v int) (float32, float32) {
+		return tx + tw*float32(i)/float32(chartHistory-1), ty + th - th*float32(v)/float32(maxPop)
+	}
+	for k, w := range worlds {
+		fish, sharks := seriesColours(k, len(worlds))
+		for i := 1; i < len(w.history); i++ {
+			x0, y0 := point(i-1, w.history[i-1].fish)
+			x1, y1 := point(i, w.history[i].fish)
+			vector.StrokeLine(screen, x0, y0, x1, y1, 1, fish, true)
+			x0, y0 = point(i-1, w.history[i-1].sharks)
+			x1, y1 = point(i, w.history[i].sharks)
+			vector.StrokeLine(screen, x0, y0, x1, y1, 1, sharks, true)
+		}
+	}
+	text.Draw(screen, fmt.Sprintf("last %d steps, max %d", len(worlds[0].history), maxPop),
+		basicfont.Face7x13, int(tx), int(py+pad+10), color.White)
+
+	// Phase space: fish across, sharks up, older points fainter.
+	fx, fy := px+pw*2/3+pad, ty
+	fw, fh := pw/3-2*pad, th
+	vector.StrokeRect(screen, fx, fy, fw, fh, 1, chartAxis, false)
+	phase := func(s popSample) (float32, float32) {
+		return fx + fw*float32(s.fish)/float32(maxFish), fy + fh - fh*float32(s.sharks)/float32(maxSharks)
+	}
+	for k, w := range worlds {
+		trail := color.RGBA{255, 255, 255, 255}
+		if len(worlds) > 1 {
+			trail, _ = seriesColours(k, len(worlds))
+		}
+		last := len(w.history) - 1
+		for i := 1; i <= last; i++ {
+			x0, y0 := phase(w.history[i-1])
+			x1, y1 := phase(w.history[i])
+			a := float32(40+215*i/last) / 255
+			c := color.RGBA{uint8(float32(trail.R) * a), uint8(float32(trail.G) * a), uint8(float32(trail.B) * a), uint8(255 * a)}
+			vector.StrokeLine(screen, x0, y0, x1, y1, 1, c, true)
+		}
+		x, y := phase(w.history[last])
+		vector.FillCircle(screen, x, y, 3, trail, true)
+	}
+	text.Draw(screen, "fish vs sharks", basicfont.Face7x13, int(fx), int(py+pad+10), color.White)
+}
@@ -0,0 +1,88 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+import (
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"golang.org/x/image/font/basicfont"
+)
+
+// helpText lists the key bindings shown by the help overlay.
+var helpText = []string{
+	"Space     pause / resume",
+	"N         single step (while paused)",
+	"+ / -     faster / slower (steps per second)",
+	"R         restart with a new seed",
+	"Mouse     left: paint, right: erase",
+	"Wheel     zoom in / out at the cursor",
+	"Middle    drag to pan (or arrow keys)",
+	"F / M     fit the world / minimap on or off",
+	"1 2 3 4   paint fish, sharks, rocks or erase",
+	"[ / ]     smaller / larger brush",
+	"C         show / hide the population chart",
+	"V         cycle views: normal, energy, breed, age, density, predation",
+	"T         show / hide the tuning panel",
+	"P         save a screenshot (PNG)",
+	"G         start / stop recording",
+	"O         include overlays in captures on / off",
+	"H         show / hide this help",
+	"Esc       quit",
+}
+
+// handleKeys applies the keys pressed since the last frame, sending the
+// ones that change the simulation to its goroutine. It returns
+// ebiten.Termination when the user quits.
+func (g *Game) handleKeys() error {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		return ebiten.Termination
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		g.sim.do(func(s *simulation) { s.paused = !s.paused })
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyN) {
+		g.sim.do(func(s *simulation) { s.stepOnce = s.paused })
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEqual) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadAdd) {
+		g.sim.do((*simulation).faster)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyMinus) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadSubtract) {
+		g.sim.do((*simulation).slower)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyR) {
+		g.sim.do((*simulation).restart)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyH) {
+		g.help = !g.help
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyC) {
+		g.chart = !g.chart
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyV) {
+		g.cycleView()
+	}
+	return nil
+}
+
+// drawHelp draws the key bindings in a translucent box over the world.
+func (g *Game) drawHelp(screen *ebiten.Image) {
+	const lineHeight = 16
+	w := 0
+	for _, line := range helpText {
+		w = max(w, text.BoundString(basicfont.Face7x13, line).Dx())
+	}
+	h := len(helpText) * lineHeight
+
+	x, y := 8, 28
+	vector.FillRect(screen, float32(x), float32(y), float32(w+16), float32(h+12), color.RGBA{0, 0, 0, 200}, false)
+	text.Draw(screen, strings.Join(helpText, "\n"), text.FaceWithLineHeight(basicfont.Face7x13, lineHeight),
+		x+8, y+18, color.White)
+}
@@ -0,0 +1,161 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// maxBrush is the largest brush radius, in display cells.
+const maxBrush = 32
+
+// paintTools are the kinds painted with the left mouse button, selected
+// with the number keys 1 to 4.
+var paintTools = []struct {
+	key  ebiten.Key
+	kind CellType
+	name string
+}{
+	{ebiten.Key1, FishCell, "fish"},
+	{ebiten.Key2, SharkCell, "shark"},
+	{ebiten.Key3, RockCell, "rock"},
+	{ebiten.Key4, Empty, "erase"},
+}
+
+// paintState is the painting tool of the graphics window.
+type paintState struct {
+	tool    int  // index into paintTools
+	brush   int  // brush radius in display cells (1 = a single cell)
+	drawing bool // a mouse button was held on the previous frame
+	lastX   int  // world cell painted on the previous frame
+	lastY   int
+
+	pending []stroke // strokes not yet sent to the simulation
+}
+
+// stroke is one dab of the brush: a disc of radius r world cells around
+// cell (x, y) filled with kind.
+type stroke struct {
+	x, y, r int
+	kind    CellType
+}
+
+// handlePaintKeys selects the tool (1-4) and brush size ([ and ]).
+func (g *Game) handlePaintKeys() {
+	for i, t := range paintTools {
+		if inpututil.IsKeyJustPressed(t.key) {
+			g.paint.tool = i
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyBracketRight) {
+		g.paint.brush = min(g.paint.brush+1, maxBrush)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyBracketLeft) {
+		g.paint.brush = max(g.paint.brush-1, 1)
+	}
+}
+
+// handleMouse paints with the current tool while the left button is held
+// and erases while the right button is held. Fast drags are filled in
+// with a line from the cell painted on the previous frame. The strokes
+// are sent to the simulation goroutine by sendStrokes.
+func (g *Game) handleMouse() {
+	kind := paintTools[g.paint.tool].kind
+	pressed := ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft)
+	if ebiten.IsMouseButtonPressed(ebiten.MouseButtonRight) {
+		kind, pressed = Empty, true
+	}
+	x, y, ok := g.cursorCell()
+	if !pressed || !ok {
+		g.paint.drawing = false
+		return
+	}
+
+	if !g.paint.drawing {
+		g.paint.lastX, g.paint.lastY = x, y
+	}
+	// Join the strokes the short way round the torus, as the camera may
+	// show the world wrapped.
+	n := g.params.GridSize
+	dx, dy := torusDelta(x-g.paint.lastX, n), torusDelta(y-g.paint.lastY, n)
+	steps := max(abs(dx), abs(dy), 1)
+	r := g.brushCells()
+	for i := 1; i <= steps; i += max(1, r/2) {
+		g.paint.pending = append(g.paint.pending, stroke{g.paint.lastX + dx*i/steps, g.paint.lastY + dy*i/steps, r, kind})
+	}
+	g.paint.pending = append(g.paint.pending, stroke{x, y, r, kind})
+	g.paint.drawing = true
+	g.paint.lastX, g.paint.lastY = x, y
+}
+
+// sendStrokes sends the strokes painted so far to the simulation as one
+// command. If its queue is full, they are kept and sent with those of a
+// later frame, so painting during a slow step never holds up the window.
+func (g *Game) sendStrokes() {
+	strokes := g.paint.pending
+	if len(strokes) == 0 {
+		return
+	}
+	if g.sim.tryDo(func(s *simulation) {
+		for _, st := range strokes {
+			s.paintBrush(st.x, st.y, st.r, st.kind)
+		}
+	}) {
+		g.paint.pending = nil
+	}
+}
+
+// cursorCell returns the world cell under the mouse cursor, looking
+// through the camera, in whichever tile it is. Each display cell covers
+// GridSize/display world cells.
+func (g *Game) cursorCell() (x, y int, ok bool) {
+	_, tx, ty, ok := g.tileAt(ebiten.CursorPosition())
+	if !ok {
+		return 0, 0, false
+	}
+	dx, dy := g.screenToDisplay(tx, ty)
+	n := g.params.GridSize
+	scale := float64(n) / float64(g.display)
+	return min(int(dx*scale), n-1), min(int(dy*scale), n-1), true
+}
+
+// torusDelta returns the shortest signed distance equivalent to d on a
+// ring of n cells.
+func torusDelta(d, n int) int {
+	d %= n
+	switch {
+	case d > n/2:
+		d -= n
+	case d < -n/2:
+		d += n
+	}
+	return d
+}
+
+// brushCells returns the brush radius in world cells, so the brush covers
+// the same part of the window when the world is downsampled.
+func (g *Game) brushCells() int {
+	return g.paint.brush * max(1, g.params.GridSize/g.display)
+}
+
+// paintBrush fills the disc of radius r world cells around cell (cx, cy),
+// wrapping around the torus, with kind. The worlds of a comparison are
+// all painted alike, so they stay comparable.
+func (s *simulation) paintBrush(cx, cy, r int, kind CellType) {
+	n := s.worlds[0].params.GridSize
+	r--
+	for dy := -r; dy <= r; dy++ {
+		for dx := -r; dx <= r; dx++ {
+			if dx*dx+dy*dy > r*r+r {
+				continue
+			}
+			for _, w := range s.worlds {
+				w.ocean.SetCell(((cx+dx)%n+n)%n, ((cy+dy)%n+n)%n, kind)
+			}
+		}
+	}
+}
@@ -0,0 +1,530 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// publishInterval is the shortest time between two snapshots, so that a
+// fast simulation spends its time stepping rather than rasterising frames
+// the screen cannot show.
+const publishInterval = time.Second / 60
+
+// simSpeeds are the target speeds selectable with + and -, in steps per
+// second, slowest first; 0 runs the simulation as fast as it can.
+var simSpeeds = []float64{1, 2, 5, 10, 15, 30, 60, 120, 250, 500, 1000, 2000, 5000, 0}
+
+// snapshot is an immutable picture of the simulation published for the
+// render loop: the rasterised worlds and everything the overlays show.
+type snapshot struct {
+	step             int
+	worlds           []worldSnapshot
+	view             int     // view mode the pixels were rendered in
+	detail           region  // world cells rendered one pixel each into worldSnapshot.detail
+	heatMin, heatMax float64 // range of an attribute view, shared by all worlds
+	paused           bool
+	target           float64 // target steps per second (0 = unlimited)
+	rate             float64 // steps per second achieved over the last second
+	done             bool    // the configured number of steps has been reached
+}
+
+// worldSnapshot is the part of a snapshot showing one world.
+type worldSnapshot struct {
+	label        string
+	params       Params // current parameters, as changed by the tuning panel
+	stopped      string // -stopOn condition that paused the world ("" = none)
+	fish, sharks int
+	pixels       []byte      // display x display RGBA image of the world
+	detail       []byte      // RGBA image of snapshot.detail (nil = none)
+	history      []popSample // populations of the last chartHistory steps
+}
+
+// simWorld is one world of the simulation. A comparison runs several,
+// built from the same seed and layout, each with its own parameters.
+type simWorld struct {
+	ocean   Ocean
+	params  Params
+	label   string // parameter set shown in the world's HUD ("" when alone)
+	history []popSample
+	heat    heatmap      // values of an attribute view
+	stats   *StatsWriter // populations and parameter changes (nil = no -csv)
+
+	stop      *stopChecker // -stopOn conditions (nil = none)
+	stopped   string       // condition that held, until the next restart
+	stoppedAt int          // step at which it held
+}
+
+// newSimWorld builds the world of one parameter set, writing its
+// statistics to csv if it is not empty.
+func newSimWorld(set compareSet, csv string) (*simWorld, error) {
+	ocean, err := newOcean(set.params)
+	if err != nil {
+		return nil, fmt.Errorf("creating world: %w", err)
+	}
+	w := &simWorld{ocean: ocean, params: set.params, label: set.label}
+	w.stop, _ = newStopChecker(set.params) // already validated by checkParams
+	if csv != "" {
+		if w.stats, err = NewStatsWriter(csv, set.params.StatsFormat, newMetadata(set.params)); err != nil {
+			return nil, fmt.Errorf("creating CSV file: %w", err)
+		}
+	}
+	w.record(0)
+	return w, nil
+}
+
+// speedString describes the speed of the simulation for the HUD.
+func (snap *snapshot) speedString() string {
+	switch {
+	case snap.paused:
+		return "paused"
+	case snap.target == 0:
+		return fmt.Sprintf("max speed (%.0f steps/s)", snap.rate)
+	default:
+		return fmt.Sprintf("%g steps/s (%.0f)", snap.target, snap.rate)
+	}
+}
+
+// simulation runs the worlds on its own goroutine, independently of the
+// Ebiten render loop, so a slow step does not drop frames and a fast
+// display does not hold the simulation back. The goroutine owns the
+// worlds: the render loop only reads the snapshots it publishes and
+// changes the worlds by sending commands, which run on the simulation
+// goroutine between two steps. All worlds step in lockstep.
+type simulation struct {
+	worlds  []*simWorld
+	steps   int // steps to run
+	display int // side of the published images in pixels
+	step    int
+	view    int // view mode to render
+
+	predation bool // fish eaten are counted, since the predation view was chosen
+
+	paused   bool
+	stepOnce bool    // take one step while paused
+	target   float64 // steps per second (0 = as fast as possible)
+	rate     float64 // steps per second achieved over the last second
+	counted  int     // steps taken since rateStart
+	dirty    bool    // something changed since the last snapshot
+
+	commands chan func(*simulation)
+	quit     chan struct{}
+	finished chan struct{}
+	started  time.Time
+
+	latest     atomic.Pointer[snapshot]
+	consumed   atomic.Bool // the latest snapshot has been taken by the render loop
+	free       chan []byte // pixel buffers of snapshots no longer shown
+	freeDetail chan []byte // detail buffers of snapshots no longer shown
+
+	// The render loop asks for the cells it is zoomed in on with
+	// wantDetail; detail is the region of the latest snapshot.
+	wantDetail atomic.Pointer[region]
+	detail     region
+}
+
+// newSimulation builds a world for each parameter set and publishes the
+// first snapshot, rendered display pixels wide. With -csv, each world
+// writes its statistics: to the -csv file itself when it is alone, or to
+// one file per world (see comparePath). Call start to run it.
+func newSimulation(sets []compareSet, display int) (*simulation, error) {
+	p := sets[0].params
+	s := &simulation{
+		steps:    p.Steps,
+		display:  display,
+		target:   p.StepsPerSec,
+		commands: make(chan func(*simulation), 64),
+		quit:     make(chan struct{}),
+		finished: make(chan struct{}),
+		free:     make(chan []byte, 2*len(sets)),
+	}
+	s.freeDetail = make(chan []byte, 2*len(sets))
+	for i, set := range sets {
+		csv := p.CSVFile
+		if csv != "" && len(sets) > 1 {
+			csv = comparePath(csv, i)
+		}
+		w, err := newSimWorld(set, csv)
+		if err != nil {
+			s.closeStats()
+			return nil, err
+		}
+		s.worlds = append(s.worlds, w)
+	}
+	s.checkStop()
+	s.publish()
+	return s, nil
+}
+
+// start runs the simulation on a new goroutine.
+func (s *simulation) start() {
+	s.started = time.Now()
+	go s.run()
+}
+
+// stop ends the simulation goroutine, waits for it to return and closes
+// the statistics files.
+func (s *simulation) stop() error {
+	close(s.quit)
+	<-s.finished
+	return s.closeStats()
+}
+
+// closeStats records how the run ended in the statistics files and
+// closes them. Closing the window early counts as quitting.
+func (s *simulation) closeStats() error {
+	res := RunResult{Steps: s.step, Elapsed: time.Since(s.started)}
+	if s.step < s.steps {
+		res.StopReason = StopQuit
+	}
+	var first error
+	for _, w := range s.worlds {
+		if w.stats != nil {
+			r := res
+			if w.stopped != "" && w.stoppedAt == s.step {
+				r.StopReason = w.stopped // still where -stopOn paused it
+			}
+			if err := w.stats.Close(r); err != nil && first == nil {
+				first = err
+			}
+		}
+	}
+	return first
+}
+
+// do queues cmd to run on the simulation goroutine before its next step.
+// It waits while the queue is full, so it is only used for key presses;
+// input that arrives every frame is sent with tryDo.
+func (s *simulation) do(cmd func(*simulation)) { s.commands <- cmd }
+
+// tryDo queues cmd like do unless the queue is full, as it can be during
+// a slow step, and reports whether it did.
+func (s *simulation) tryDo(cmd func(*simulation)) bool {
+	select {
+	case s.commands <- cmd:
+		return true
+	default:
+		return false
+	}
+}
+
+// snapshot returns the latest snapshot and lets the simulation publish
+// the next one.
+func (s *simulation) snapshot() *snapshot {
+	s.consumed.Store(true)
+	return s.latest.Load()
+}
+
+// release hands back the pixels of a snapshot the render loop no longer
+// shows, so the next snapshot can reuse them.
+func (s *simulation) release(snap *snapshot) {
+	for _, w := range snap.worlds {
+		select {
+		case s.free <- w.pixels:
+		default:
+		}
+		if w.detail != nil {
+			select {
+			case s.freeDetail <- w.detail:
+			default:
+			}
+		}
+	}
+}
+
+// requestDetail asks for region r of the worlds to be rendered at cell
+// resolution from the next snapshot on, or for none if r is nil. The
+// render loop calls it when the camera is zoomed in on a downsampled
+// world.
+func (s *simulation) requestDetail(r *region) { s.wantDetail.Store(r) }
+
+// wantedDetail returns the region to render at cell resolution. Only the
+// normal view has one: attribute views stay at display resolution.
+func (s *simulation) wantedDetail() region {
+	if r := s.wantDetail.Load(); r != nil && s.view == ViewNormal {
+		return *r
+	}
+	return region{}
+}
+
+// run steps the world at the target speed, applies commands as they
+// arrive and publishes a snapshot whenever something changed, the last
+// one has been shown and publishInterval has passed. Every queued command
+// runs before the next step, so the queue empties after each slow step.
+func (s *simulation) run() {
+	defer close(s.finished)
+	ready := make(chan time.Time)
+	close(ready)
+
+	var next, lastPublish time.Time
+	rateStart := time.Now()
+	for {
+		for queued := true; queued; {
+			select {
+			case cmd := <-s.commands:
+				cmd(s)
+				s.dirty = true
+			default:
+				queued = false
+			}
+		}
+		if s.wantedDetail() != s.detail {
+			s.dirty = true
+		}
+
+		now := time.Now()
+		if elapsed := now.Sub(rateStart); elapsed >= time.Second {
+			s.rate = float64(s.counted) / elapsed.Seconds()
+			s.counted, rateStart = 0, now
+			s.dirty = true
+		}
+		if s.dirty && s.consumed.Load() && now.Sub(lastPublish) >= publishInterval {
+			s.publish()
+			lastPublish = now
+		}
+
+		wait := publishInterval
+		if s.canStep() {
+			if s.target == 0 || !now.Before(next) {
+				s.advance()
+				next = s.nextStep(next, now)
+				wait = 0
+			} else {
+				wait = min(wait, next.Sub(now))
+			}
+		}
+
+		timeout := (<-chan time.Time)(ready)
+		if wait > 0 {
+			timeout = time.After(wait)
+		}
+		select {
+		case cmd := <-s.commands:
+			cmd(s)
+			s.dirty = true
+		case <-s.quit:
+			return
+		case <-timeout:
+		}
+	}
+}
+
+// canStep reports whether the simulation should take another step.
+func (s *simulation) canStep() bool {
+	return (!s.paused || s.stepOnce) && s.step < s.steps
+}
+
+// nextStep returns when the step after the one taken at now is due. After
+// a stall (a slow step or a busy machine) at most one step is made up, so
+// the simulation does not race to catch up.
+func (s *simulation) nextStep(next, now time.Time) time.Time {
+	if s.target == 0 {
+		return now
+	}
+	interval := time.Duration(float64(time.Second) / s.target)
+	if next.Before(now.Add(-interval)) {
+		next = now.Add(-interval)
+	}
+	return next.Add(interval)
+}
+
+// advance takes one simulation step in every world.
+func (s *simulation) advance() {
+	for _, w := range s.worlds {
+		if w.params.Threads > 1 {
+			w.ocean.StepParallel(w.params.Threads)
+		} else {
+			w.ocean.Step()
+		}
+	}
+	s.step++
+	for _, w := range s.worlds {
+		w.record(s.step)
+	}
+	s.checkStop()
+	s.stepOnce = false
+	s.counted++
+	s.dirty = true
+}
+
+// checkStop feeds the populations to the -stopOn conditions of every
+// world and pauses the simulation when one of them holds, so the moment
+// can be looked at. Each world stops once: after resuming, it runs on
+// regardless until the next restart.
+func (s *simulation) checkStop() {
+	for _, w := range s.worlds {
+		if w.stop == nil || w.stopped != "" {
+			continue
+		}
+		fish, sharks := w.ocean.Count()
+		n := w.ocean.Dim()
+		if reason := w.stop.check(fish, sharks, n*n); reason != "" {
+			w.stopped, w.stoppedAt = reason, s.step
+			s.paused = true
+			s.dirty = true
+		}
+	}
+}
+
+// setView renders view mode view from the next snapshot on. The fish
+// eaten in each cell are only counted from the first time the predation
+// view is chosen, as the counts take 4 bytes per cell.
+func (s *simulation) setView(view int) {
+	s.view = view
+	if view == ViewPredation && !s.predation {
+		s.predation = true
+		for _, w := range s.worlds {
+			w.ocean.TrackPredation()
+		}
+	}
+}
+
+// faster and slower move the target speed to the next of simSpeeds above
+// or below the current one.
+func (s *simulation) faster() {
+	for _, v := range simSpeeds {
+		if v == 0 || (s.target != 0 && v > s.target) {
+			s.target = v
+			return
+		}
+	}
+}
+
+func (s *simulation) slower() {
+	for i := len(simSpeeds) - 2; i >= 0; i-- {
+		if s.target == 0 || simSpeeds[i] < s.target {
+			s.target = simSpeeds[i]
+			return
+		}
+	}
+}
+
+// restart replaces every world with a new one built from the same
+// parameters and the next seed, and starts counting steps again. The
+// worlds of a comparison still share their layout. The statistics files
+// record the new seed, and their steps start again from 0. If a world
+// cannot be built, the error is printed and the simulation carries on.
+func (s *simulation) restart() {
+	oceans := make([]Ocean, len(s.worlds))
+	for i, w := range s.worlds {
+		p := w.params
+		p.Seed++
+		var err error
+		if oceans[i], err = newOcean(p); err != nil {
+			fmt.Println("Error restarting:", err)
+			return
+		}
+	}
+	for i, w := range s.worlds {
+		w.params.Seed++
+		if w.stats != nil {
+			w.stats.Set(s.step, "seed", w.params.Seed)
+		}
+		w.ocean = oceans[i]
+		if s.predation {
+			w.ocean.TrackPredation()
+		}
+		w.history = w.history[:0]
+		w.record(0)
+		w.stop, _ = newStopChecker(w.params)
+		w.stopped = ""
+	}
+	s.step = 0
+	s.checkStop()
+}
+
+// tune sets parameter t of world i, or of every world if i is -1, to
+// value from the next step on, and logs the change with the current step
+// in the statistics files. A value the world cannot use is printed and
+// ignored.
+func (s *simulation) tune(i int, t tuneParam, value int) {
+	for k, w := range s.worlds {
+		if i >= 0 && k != i {
+			continue
+		}
+		if *t.field(&w.params) == value {
+			continue
+		}
+		p := w.params
+		*t.field(&p) = value
+		if err := p.Validate(); err != nil {
+			fmt.Println("Error tuning:", err)
+			continue
+		}
+		w.params = p
+		w.ocean.SetParams(w.params)
+		if w.stats != nil {
+			w.stats.Set(s.step, t.name, value)
+		}
+	}
+}
+
+// publish renders the worlds in the current view mode into a snapshot
+// and makes it the latest one. Attribute views of a comparison share one
+// range, so the same colour means the same value in every world. The
+// region the render loop is zoomed in on is also rendered one pixel per
+// cell, so zooming into a downsampled world shows every creature.
+func (s *simulation) publish() {
+	s.detail = s.wantedDetail()
+	snap := &snapshot{
+		step:   s.step,
+		view:   s.view,
+		detail: s.detail,
+		paused: s.paused,
+		target: s.target,
+		rate:   s.rate,
+		done:   s.step >= s.steps,
+	}
+	if s.view != ViewNormal {
+		for _, w := range s.worlds {
+			w.computeHeatmap(s.view, s.display)
+		}
+		snap.heatMin, snap.heatMax = heatRange(s.worlds)
+	}
+
+	for _, w := range s.worlds {
+		var pix []byte
+		select {
+		case pix = <-s.free:
+		default:
+			pix = make([]byte, s.display*s.display*4)
+		}
+		if s.view == ViewNormal {
+			rasterize(w.ocean, s.display, pix)
+		} else {
+			w.paintHeatmap(pix, s.view, snap.heatMin, snap.heatMax)
+		}
+		var detail []byte
+		if r := s.detail; r.w > 0 {
+			select {
+			case detail = <-s.freeDetail:
+			default:
+			}
+			if cap(detail) < r.w*r.h*4 {
+				detail = make([]byte, r.w*r.h*4)
+			}
+			detail = detail[:r.w*r.h*4]
+			rasterizeRegion(w.ocean, r, detail)
+		}
+		fish, sharks := w.ocean.Count()
+		snap.worlds = append(snap.worlds, worldSnapshot{
+			label:   w.label,
+			params:  w.params,
+			stopped: w.stopped,
+			fish:    fish,
+			sharks:  sharks,
+			pixels:  pix,
+			detail:  detail,
+			history: append([]popSample(nil), w.history...),
+		})
+	}
+
+	s.consumed.Store(false)
+	s.latest.Store(snap)
+	s.dirty = false
+}
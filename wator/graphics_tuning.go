@@ -0,0 +1,191 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"runtime"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"golang.org/x/image/font/basicfont"
+)
+
+// Size of the tuning panel in window pixels: its width and the height of
+// each row, a name and value above a slider track.
+const (
+	tuningWidth = 220
+	tuningRow   = 30
+)
+
+// tuneParam is a parameter the tuning panel can change while the
+// simulation runs. name is the flag that sets it, which is also how its
+// changes are logged in the statistics file.
+type tuneParam struct {
+	name     string
+	min, max int
+	field    func(*Params) *int
+}
+
+// tuningPanel is the state of the panel of sliders toggled with T.
+type tuningPanel struct {
+	open   bool
+	params []tuneParam
+	target int // world the sliders change (-1 = every world)
+	drag   int // slider being dragged (-1 = none)
+	sent   int // value last sent for the dragged slider
+}
+
+// newTuningPanel builds the sliders for the worlds of sets. Their ranges
+// start at 1 and reach at least the largest starting value, so every
+// world can be tuned back to where it began. The times of a chunked world
+// must fit its cell counters, and threads must not exceed the rows.
+func newTuningPanel(sets []compareSet) tuningPanel {
+	p := sets[0].params
+	timeMax := 40
+	threadMax := min(p.GridSize, 2*runtime.NumCPU())
+	for _, set := range sets {
+		timeMax = max(timeMax, set.params.FishBreed, set.params.SharkBreed, set.params.Starve)
+		threadMax = max(threadMax, set.params.Threads)
+	}
+	if p.Chunked {
+		timeMax = min(timeMax, maxCellCounter)
+	}
+	return tuningPanel{
+		params: []tuneParam{
+			{"fishBreed", 1, timeMax, func(p *Params) *int { return &p.FishBreed }},
+			{"sharkBreed", 1, timeMax, func(p *Params) *int { return &p.SharkBreed }},
+			{"starve", 1, timeMax, func(p *Params) *int { return &p.Starve }},
+			{"threads", 1, threadMax, func(p *Params) *int { return &p.Threads }},
+		},
+		target: -1,
+		drag:   -1,
+	}
+}
+
+// tuningRect returns where the panel is drawn: in the top-right corner of
+// the window, below the minimap if it is shown. A comparison adds a title
+// row that chooses the world the sliders change.
+func (g *Game) tuningRect() (x, y, w, h int) {
+	y = 28
+	if _, my, size, ok := g.minimapRect(); ok {
+		y = my + size + 8
+	}
+	rows := len(g.tuning.params)
+	if len(g.imgs) > 1 {
+		rows++
+	}
+	return g.width - tuningWidth - 8, y, tuningWidth, rows*tuningRow + 8
+}
+
+// tuningTarget names the world the sliders change.
+func (g *Game) tuningTarget() string {
+	switch {
+	case len(g.imgs) == 1:
+		return ""
+	case g.tuning.target < 0:
+		return "all worlds"
+	default:
+		return g.snap.worlds[g.tuning.target].label
+	}
+}
+
+// tuningValue returns the value slider i shows: the one being dragged, or
+// the current value of the target world (the first when tuning all).
+func (g *Game) tuningValue(i int) int {
+	if g.tuning.drag == i && g.tuning.sent > 0 {
+		return g.tuning.sent
+	}
+	w := g.snap.worlds[max(g.tuning.target, 0)]
+	return *g.tuning.params[i].field(&w.params)
+}
+
+// handleTuning toggles the panel with T and, while it is open, drags its
+// sliders with the left button, sending each new value to the simulation,
+// which uses it from the next step on. A value that does not fit in the
+// simulation's queue is sent again on a later frame. Clicking the title
+// row of a comparison cycles the target through all worlds and each one.
+// It returns true while the mouse is on the panel so it does not paint.
+func (g *Game) handleTuning() bool {
+	t := &g.tuning
+	if inpututil.IsKeyJustPressed(ebiten.KeyT) {
+		t.open = !t.open
+		t.drag = -1
+	}
+	if !t.open {
+		return false
+	}
+
+	x, y, w, h := g.tuningRect()
+	mx, my := ebiten.CursorPosition()
+	inside := mx >= x && my >= y && mx < x+w && my < y+h
+	if !ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+		t.drag = -1
+		return inside
+	}
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) && inside {
+		row := (my - y - 4) / tuningRow
+		if len(g.imgs) > 1 {
+			if row == 0 {
+				t.target++
+				if t.target == len(g.imgs) {
+					t.target = -1
+				}
+				return true
+			}
+			row--
+		}
+		if row >= 0 && row < len(t.params) {
+			t.drag, t.sent = row, 0
+		}
+	}
+	if t.drag < 0 {
+		return inside
+	}
+
+	p := t.params[t.drag]
+	frac := float64(mx-x-8) / float64(w-16)
+	value := p.min + int(math.Round(math.Max(0, math.Min(1, frac))*float64(p.max-p.min)))
+	if value != t.sent {
+		target := t.target
+		if g.sim.tryDo(func(s *simulation) { s.tune(target, p, value) }) {
+			t.sent = value
+		}
+	}
+	return true
+}
+
+// drawTuning draws the panel, if it is open, with a slider for each
+// parameter showing its current value within its range.
+func (g *Game) drawTuning(screen *ebiten.Image) {
+	if !g.tuning.open {
+		return
+	}
+	x, y, w, h := g.tuningRect()
+	vector.FillRect(screen, float32(x), float32(y), float32(w), float32(h), color.RGBA{0, 0, 0, 200}, false)
+	vector.StrokeRect(screen, float32(x), float32(y), float32(w), float32(h), 1, chartAxis, false)
+
+	row := y + 4
+	if name := g.tuningTarget(); name != "" {
+		text.Draw(screen, "Tuning: "+name, basicfont.Face7x13, x+8, row+18, color.White)
+		row += tuningRow
+	}
+	for i, p := range g.tuning.params {
+		value := g.tuningValue(i)
+		text.Draw(screen, fmt.Sprintf("%-10s %d", p.name, value), basicfont.Face7x13, x+8, row+12, color.White)
+		text.Draw(screen, fmt.Sprintf("%d-%d", p.min, p.max), basicfont.Face7x13, x+w-64, row+12, chartAxis)
+
+		tx, tw := float32(x+8), float32(w-16)
+		vector.FillRect(screen, tx, float32(row+19), tw, 4, chartAxis, false)
+		frac := float32(value-p.min) / float32(max(1, p.max-p.min))
+		vector.FillRect(screen, tx+frac*tw-3, float32(row+15), 6, 12, color.White, false)
+		row += tuningRow
+	}
+}
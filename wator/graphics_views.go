@@ -0,0 +1,239 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"golang.org/x/image/font/basicfont"
+)
+
+// View modes of the graphics window, cycled with V. ViewNormal draws the
+// fish and sharks; the others colour each display cell by an attribute.
+const (
+	ViewNormal    = iota
+	ViewEnergy    // mean shark energy, 0 (starving) to Starve
+	ViewBreed     // mean breed counter of fish and sharks
+	ViewAge       // mean age of fish and sharks
+	ViewDensity   // share of occupied cells, smoothed over neighbouring blocks
+	ViewPredation // fish eaten in each cell since the view was first shown
+	numViews
+)
+
+// viewNames are the legend titles of the view modes.
+var viewNames = [numViews]string{
+	"", "shark energy", "breed counter", "age", "local density", "predation events",
+}
+
+// densityRadius is the radius, in display cells, of the box blur applied
+// to the density view.
+const densityRadius = 2
+
+// heatStops is the palette of the attribute views, from the lowest value
+// to the highest.
+var heatStops = [][3]float64{
+	{20, 10, 60}, {120, 20, 140}, {220, 60, 70}, {250, 160, 30}, {255, 250, 200},
+}
+
+// heatmap holds the values of the current attribute view at display
+// resolution. NaN marks blocks without a value, which are drawn as water
+// or rock.
+type heatmap struct {
+	values   []float64
+	rocks    []bool
+	min, max float64
+}
+
+// heatRange returns the range covering the heatmaps of all worlds, from
+// the lowest of their minimums to the highest of their maximums.
+func heatRange(worlds []*simWorld) (lo, hi float64) {
+	for i, w := range worlds {
+		if i == 0 {
+			lo, hi = w.heat.min, w.heat.max
+			continue
+		}
+		lo, hi = min(lo, w.heat.min), max(hi, w.heat.max)
+	}
+	return lo, hi
+}
+
+// heatColour returns the palette colour of t in [0, 1].
+func heatColour(t float64) [3]byte {
+	t = math.Max(0, math.Min(1, t)) * float64(len(heatStops)-1)
+	i := min(int(t), len(heatStops)-2)
+	f := t - float64(i)
+	var c [3]byte
+	for k := range c {
+		c[k] = byte(heatStops[i][k] + (heatStops[i+1][k]-heatStops[i][k])*f)
+	}
+	return c
+}
+
+// cycleView switches to the next view mode, which the simulation renders
+// from its next snapshot on.
+func (g *Game) cycleView() {
+	g.view = (g.view + 1) % numViews
+	view := g.view
+	g.sim.do(func(s *simulation) { s.setView(view) })
+}
+
+// computeHeatmap fills w.heat with the values of view mode view, one per
+// cell of a display x display image, and sets the range they cover. Like
+// the normal view, large blocks are sampled on a lattice of at most
+// maxBlockSamples cells a side, so a frame costs the same at any world
+// size; the predation of a block is the total its samples suggest.
+func (w *simWorld) computeHeatmap(view, display int) {
+	n := display
+	h := &w.heat
+	if h.values == nil {
+		h.values = make([]float64, n*n)
+		h.rocks = make([]bool, n*n)
+	}
+
+	size := w.params.GridSize
+	observed := 0.0
+	for j := 0; j < n; j++ {
+		y0, y1 := blockBounds(j, n, size)
+		for i := 0; i < n; i++ {
+			x0, x1 := blockBounds(i, n, size)
+			stepX, stepY := max(1, (x1-x0)/maxBlockSamples), max(1, (y1-y0)/maxBlockSamples)
+			sum, count, rocks, cells := 0.0, 0, 0, 0
+			for y := y0; y < y1; y += stepY {
+				for x := x0; x < x1; x += stepX {
+					cells++
+					if view == ViewPredation {
+						sum += float64(w.ocean.Predation(x, y))
+						count++
+						continue
+					}
+					info := w.ocean.Info(x, y)
+					switch {
+					case info.Kind == RockCell:
+						rocks++
+					case view == ViewDensity:
+						count++
+						if info.Kind != Empty {
+							sum++
+						}
+					case info.Kind == Empty:
+					case view == ViewEnergy && info.Kind == SharkCell:
+						sum += float64(info.Energy)
+						count++
+					case view == ViewBreed:
+						sum += float64(info.Breed)
+						count++
+					case view == ViewAge && info.Age >= 0:
+						sum += float64(info.Age)
+						count++
+					}
+				}
+			}
+			v := math.NaN()
+			if count > 0 {
+				v = sum / float64(count)
+				if view == ViewPredation {
+					// The total in the block, not the mean.
+					v = sum * float64((x1-x0)*(y1-y0)) / float64(cells)
+				}
+				observed = math.Max(observed, v)
+			}
+			h.values[j*n+i] = v
+			h.rocks[j*n+i] = rocks*2 > cells
+		}
+	}
+
+	h.min, h.max = 0, math.Max(observed, 1)
+	switch view {
+	case ViewEnergy:
+		h.max = float64(w.params.Starve)
+	case ViewBreed:
+		h.max = float64(max(w.params.FishBreed, w.params.SharkBreed))
+	case ViewDensity:
+		w.blurHeatmap(display)
+		h.max = 1
+	}
+}
+
+// blurHeatmap smooths the density view with a box blur of densityRadius
+// display cells, wrapping around the torus.
+func (w *simWorld) blurHeatmap(display int) {
+	n := display
+	src := append([]float64(nil), w.heat.values...)
+	for j := 0; j < n; j++ {
+		for i := 0; i < n; i++ {
+			sum, count := 0.0, 0
+			for dy := -densityRadius; dy <= densityRadius; dy++ {
+				for dx := -densityRadius; dx <= densityRadius; dx++ {
+					v := src[((j+dy+n)%n)*n+(i+dx+n)%n]
+					if !math.IsNaN(v) {
+						sum += v
+						count++
+					}
+				}
+			}
+			if count > 0 && !math.IsNaN(src[j*n+i]) {
+				w.heat.values[j*n+i] = sum / float64(count)
+			}
+		}
+	}
+}
+
+// paintHeatmap fills pix with the colours of attribute view view, one
+// pixel per display cell, mapping lo to hi onto the palette. Predation is
+// drawn on a square-root scale so that a few busy cells do not hide the
+// rest.
+func (w *simWorld) paintHeatmap(pix []byte, view int, lo, hi float64) {
+	h := &w.heat
+	for i, v := range h.values {
+		var c [3]byte
+		switch {
+		case h.rocks[i]:
+			c = [3]byte{byte(rockRGB[0]), byte(rockRGB[1]), byte(rockRGB[2])}
+		case math.IsNaN(v):
+			c = blend(0, 0, 1)
+		case view == ViewPredation:
+			c = heatColour(math.Sqrt(v / hi))
+		default:
+			c = heatColour((v - lo) / (hi - lo))
+		}
+		p := pix[i*4:]
+		p[0], p[1], p[2], p[3] = c[0], c[1], c[2], 255
+	}
+}
+
+// drawLegend draws the name of the current view under the HUD with a
+// colour bar from its lowest to its highest value.
+func (g *Game) drawLegend(screen *ebiten.Image) {
+	const barW, barH = 160, 10
+	x, y := 8, 24
+	view := g.snap.view
+	title := viewNames[view]
+	if view == ViewAge && g.params.Chunked {
+		title += " (not tracked by -chunked)"
+	}
+	lo, hi := fmt.Sprint(int(g.snap.heatMin)), fmt.Sprint(int(math.Round(g.snap.heatMax)))
+	if view == ViewDensity {
+		lo, hi = "0%", "100%"
+	}
+	if view == ViewPredation {
+		hi += " (sqrt scale)"
+	}
+
+	vector.FillRect(screen, float32(x), float32(y), barW+200, barH+24, color.RGBA{0, 0, 0, 170}, false)
+	for i := 0; i < barW; i++ {
+		c := heatColour(float64(i) / (barW - 1))
+		vector.FillRect(screen, float32(x+4+i), float32(y+4), 1, barH, color.RGBA{c[0], c[1], c[2], 255}, false)
+	}
+	text.Draw(screen, "View: "+title, basicfont.Face7x13, x+barW+12, y+13, color.White)
+	text.Draw(screen, lo, basicfont.Face7x13, x+4, y+barH+17, color.White)
+	hiW := text.BoundString(basicfont.Face7x13, hi).Dx()
+	text.Draw(screen, hi, basicfont.Face7x13, max(x+4+barW-hiW, x+60), y+barH+17, color.White)
+}
@@ -0,0 +1,33 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+import "testing"
+
+// TestHeatRange checks that the worlds of a comparison share one range
+// that covers every one of them.
+func TestHeatRange(t *testing.T) {
+	tests := []struct {
+		name           string
+		ranges         [][2]float64 // min and max of each world
+		wantLo, wantHi float64
+	}{
+		{"one world", [][2]float64{{2, 10}}, 2, 10},
+		{"lowest minimum elsewhere", [][2]float64{{2, 10}, {0, 5}, {1, 12}}, 0, 12},
+		{"highest maximum first", [][2]float64{{3, 20}, {1, 4}}, 1, 20},
+		{"equal ranges", [][2]float64{{0, 1}, {0, 1}, {0, 1}}, 0, 1},
+		{"negative values", [][2]float64{{-2, 3}, {-5, 1}}, -5, 3},
+	}
+	for _, tt := range tests {
+		var worlds []*simWorld
+		for _, r := range tt.ranges {
+			worlds = append(worlds, &simWorld{heat: heatmap{min: r[0], max: r[1]}})
+		}
+		if lo, hi := heatRange(worlds); lo != tt.wantLo || hi != tt.wantHi {
+			t.Errorf("%s: heatRange = %g..%g, want %g..%g", tt.name, lo, hi, tt.wantLo, tt.wantHi)
+		}
+	}
+}
@@ -7,67 +7,169 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"flag"
 	"fmt"
 	"os"
 	"os/exec"
 	"runtime"
+	"runtime/trace"
+	"strings"
 	"time"
 )
 
 // Params holds all configuration parameters for a single run of the
 // Wa-Tor simulation. These values are set from command–line flags.
 type Params struct {
-	NumShark   int    // starting population of sharks
-	NumFish    int    // starting population of fish
-	FishBreed  int    // chronons before a fish can reproduce
-	SharkBreed int    // chronons before a shark can reproduce
-	Starve     int    // chronons a shark can survive without food
-	GridSize   int    // width and height of the toroidal grid (GridSize x GridSize)
-	Threads    int    // number of goroutines to use for the parallel step
-	Steps      int    // number of simulation steps (chronons) to run
-	PrintEvery int    // how often to print the world in text mode (0 = never)
-	CSVFile    string // optional path to CSV file for population statistics
-
-	Graphics bool // if true, run the graphical (Ebiten) version instead of text mode
+	NumShark    int    // starting population of sharks
+	NumFish     int    // starting population of fish
+	FishBreed   int    // chronons before a fish can reproduce
+	SharkBreed  int    // chronons before a shark can reproduce
+	Starve      int    // chronons a shark can survive without food
+	GridSize    int    // width and height of the toroidal grid (GridSize x GridSize)
+	Threads     int    // number of goroutines to use for the parallel step
+	Steps       int    // number of simulation steps (chronons) to run
+	PrintEvery  int    // how often to print the world in text mode (0 = never)
+	CSVFile     string // optional path to CSV file for population statistics
+	StatsFormat string // format of the CSVFile: "csv" or "jsonl"
+
+	Graphics    bool    // if true, run the graphical (Ebiten) version instead of text mode
+	Interactive bool    // if true, run the interactive terminal view instead of text mode
+	Chunked     bool    // if true, store the world in compact chunks (ChunkedWorld)
+	DisplaySize int     // worlds wider than this are downsampled for display (0 = never)
+	PixelSize   int     // pixels per displayed cell when the graphics window opens
+	WindowScale float64 // size of the graphics window relative to its logical resolution
+	StepsPerSec float64 // target simulation speed in graphics mode (0 = as fast as possible)
+	RenderMode  string  // text rendering: "ascii" or "halfblock"
+	BlockMode   string  // overview blocks summarised by "majority" or "density"
+	ViewX       int     // left column of the text-mode viewport
+	ViewY       int     // top row of the text-mode viewport
+	ViewSize    int     // side of the text-mode viewport in cells (0 = overview of the whole world)
+	Compare     string  // parameter sets run side by side in graphics mode, e.g. "starve=3;starve=5"
+
+	CaptureDir   string // directory for screenshots and recordings in graphics mode
+	RecordFormat string // format of recordings: "gif" or "png"
+	CaptureHUD   bool   // include the HUD and other overlays in captures
+
+	CPUProfile string // optional path for a pprof CPU profile of the run
+	MemProfile string // optional path for a pprof heap profile taken after the run
+	TraceFile  string // optional path for a runtime/trace execution trace
+
+	Seed       int64 // random seed for the initial layout and moves (0 = time-based)
+	Replicates int   // number of runs with consecutive seeds for ensemble statistics
+
+	StopOn           string  // comma-separated early stop conditions (extinct, saturated, stationary)
+	StationaryWindow int     // steps over which populations must stay flat to be stationary
+	StationaryTol    float64 // allowed relative spread of a stationary population
+
+	SpatialCSV   string // optional path to CSV file for spatial pattern statistics
+	SpatialEvery int    // compute spatial statistics every this many steps
+	SpatialRange int    // largest distance for the pair correlation function
 }
 
-// parseParams parses command–line flags into a Params struct and performs
-// basic validation of the input values.
+// headless returns a copy of p for a run that is only measured by its
+// caller (bench, sweep, ensemble members): nothing is printed, no files
+// are written and no profiles are taken.
+func (p Params) headless() Params {
+	p.PrintEvery = 0
+	p.CSVFile, p.SpatialCSV = "", ""
+	p.CPUProfile, p.MemProfile, p.TraceFile = "", "", ""
+	p.Replicates = 1
+	return p
+}
+
+// parseParams parses command–line flags (and the -config file, if any)
+// into a Params struct and performs basic validation of the input values.
 func parseParams() Params {
 	p := Params{}
-
-	flag.IntVar(&p.NumShark, "numShark", 100, "Starting population of sharks")
-	flag.IntVar(&p.NumFish, "numFish", 200, "Starting population of fish")
-	flag.IntVar(&p.FishBreed, "fishBreed", 3, "Chronons before a fish can reproduce")
-	flag.IntVar(&p.SharkBreed, "sharkBreed", 5, "Chronons before a shark can reproduce")
-	flag.IntVar(&p.Starve, "starve", 3, "Chronons a shark can live without food")
-	flag.IntVar(&p.GridSize, "gridSize", 20, "Grid dimension (NxN)")
-	flag.IntVar(&p.Threads, "threads", 1, "Number of threads (goroutines) to use")
-	flag.IntVar(&p.Steps, "steps", 200, "Number of simulation steps (chronons)")
-	flag.IntVar(&p.PrintEvery, "printEvery", 20, "How often to print the grid (0 = never)")
-	flag.StringVar(&p.CSVFile, "csv", "", "Optional CSV file to write stats (e.g. stats.csv)")
-	flag.BoolVar(&p.Graphics, "graphics", false, "Run with graphical window (Ebiten)")
-
+	registerParamFlags(flag.CommandLine, &p)
+	config := registerConfigFlags(flag.CommandLine)
 	flag.Parse()
+	config.apply(flag.CommandLine, &p)
+	checkParams(p)
+	return p
+}
 
-	if p.NumShark < 0 || p.NumFish < 0 || p.GridSize <= 0 {
-		fmt.Println("Error: invalid parameter values")
-		os.Exit(1)
+// registerParamFlags defines one flag per Params field on fs, so that
+// subcommands such as "bench" accept the same options as a normal run.
+func registerParamFlags(fs *flag.FlagSet, p *Params) {
+	fs.IntVar(&p.NumShark, "numShark", 100, "Starting population of sharks")
+	fs.IntVar(&p.NumFish, "numFish", 200, "Starting population of fish")
+	fs.IntVar(&p.FishBreed, "fishBreed", 3, "Chronons before a fish can reproduce")
+	fs.IntVar(&p.SharkBreed, "sharkBreed", 5, "Chronons before a shark can reproduce")
+	fs.IntVar(&p.Starve, "starve", 3, "Chronons a shark can live without food")
+	fs.IntVar(&p.GridSize, "gridSize", 20, "Grid dimension (NxN)")
+	fs.IntVar(&p.Threads, "threads", 1, "Number of threads (goroutines) to use")
+	fs.IntVar(&p.Steps, "steps", 200, "Number of simulation steps (chronons)")
+	fs.IntVar(&p.PrintEvery, "printEvery", 20, "How often to print the grid (0 = never)")
+	fs.StringVar(&p.CSVFile, "csv", "", "Optional CSV file to write stats (e.g. stats.csv)")
+	fs.StringVar(&p.StatsFormat, "statsFormat", FormatCSV, "Format of the -csv file: csv (with metadata comments) or jsonl")
+	fs.BoolVar(&p.Graphics, "graphics", false, "Run with graphical window (Ebiten)")
+	fs.BoolVar(&p.Interactive, "tui", false, "Run the interactive terminal view (space, n, +/-, q)")
+	fs.BoolVar(&p.Chunked, "chunked", false, "Use compact chunked storage for huge worlds")
+	fs.IntVar(&p.DisplaySize, "displaySize", 256, "Downsample worlds wider than this for display (0 = never)")
+	fs.StringVar(&p.Compare, "compare", "", "Run these parameter sets side by side in graphics mode (e.g. \"starve=3;starve=5\")")
+	fs.IntVar(&p.PixelSize, "pixelSize", 4, "Pixels per displayed cell when the graphics window opens")
+	fs.Float64Var(&p.StepsPerSec, "sps", 15, "Target steps per second in graphics mode (0 = as fast as possible)")
+	fs.Float64Var(&p.WindowScale, "windowScale", 2, "Initial graphics window size relative to gridSize*pixelSize (capped to the screen)")
+	fs.StringVar(&p.CaptureDir, "captureDir", "captures", "Directory for screenshots (P) and recordings (G) in graphics mode")
+	fs.StringVar(&p.RecordFormat, "recordFormat", RecordGIF, "Format of recordings in graphics mode: gif (animated) or png (numbered frames)")
+	fs.BoolVar(&p.CaptureHUD, "captureHUD", true, "Include the HUD and other overlays in screenshots and recordings")
+	fs.StringVar(&p.RenderMode, "render", RenderASCII, "Text rendering: ascii or halfblock (24-bit colour, two rows per line)")
+	fs.StringVar(&p.BlockMode, "blockMode", BlockMajority, "Overview blocks show the majority kind or the density (majority, density)")
+	fs.IntVar(&p.ViewX, "viewX", 0, "Left column of the text-mode viewport")
+	fs.IntVar(&p.ViewY, "viewY", 0, "Top row of the text-mode viewport")
+	fs.IntVar(&p.ViewSize, "viewSize", 0, "Show only viewSize x viewSize cells in text mode (0 = whole world)")
+	fs.StringVar(&p.CPUProfile, "cpuprofile", "", "Write a CPU profile of the simulation loop to this file")
+	fs.StringVar(&p.MemProfile, "memprofile", "", "Write a heap profile after the simulation loop to this file")
+	fs.StringVar(&p.TraceFile, "trace", "", "Write an execution trace of the simulation loop to this file")
+	fs.Int64Var(&p.Seed, "seed", 0, "Random seed (0 = different every run)")
+	fs.IntVar(&p.Replicates, "replicates", 1, "Run this many seeds and write ensemble statistics to -csv")
+	fs.StringVar(&p.StopOn, "stopOn", "", "Stop early on any of: extinct,saturated,stationary")
+	fs.IntVar(&p.StationaryWindow, "stationaryWindow", 100, "Steps populations must stay flat to count as stationary")
+	fs.Float64Var(&p.StationaryTol, "stationaryTol", 0.02, "Allowed relative spread of a stationary population")
+	fs.StringVar(&p.SpatialCSV, "spatialCSV", "", "Optional CSV file for spatial statistics (clusters, g(r), Moran's I)")
+	fs.IntVar(&p.SpatialEvery, "spatialEvery", 1, "Compute spatial statistics every this many steps")
+	fs.IntVar(&p.SpatialRange, "spatialRange", 5, "Largest distance for the pair correlation function")
+}
+
+// checkParams validates the input values with Params.ValidateRun and exits
+// with a list of every problem if they are unusable.
+func checkParams(p Params) {
+	err := p.ValidateRun()
+	if err == nil {
+		return
 	}
-	if p.Steps <= 0 {
-		fmt.Println("Error: steps must be > 0")
-		os.Exit(1)
+	fmt.Println("Error: invalid parameters:")
+	for _, line := range strings.Split(err.Error(), "\n") {
+		fmt.Println("  -", line)
 	}
-
-	return p
+	os.Exit(1)
 }
 
 // main is the entry point of the Wa-Tor simulation. It parses parameters,
 // prints a short summary and then runs either the text or graphical
 // version of the simulation.
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "bench":
+			runBench(os.Args[2:])
+			return
+		case "plot":
+			runPlot(os.Args[2:])
+			return
+		case "sweep":
+			runSweep(os.Args[2:])
+			return
+		case "analyze":
+			runAnalyze(os.Args[2:])
+			return
+		}
+	}
+
 	params := parseParams()
+	params.Seed = resolveSeed(params.Seed)
 
 	fmt.Println("Wa-Tor Simulation")
 	fmt.Println("-----------------")
@@ -80,13 +182,26 @@ func main() {
 	fmt.Printf("Threads     : %d\n", params.Threads)
 	fmt.Printf("Steps       : %d\n", params.Steps)
 	fmt.Printf("PrintEvery  : %d\n", params.PrintEvery)
+	fmt.Printf("Seed        : %d\n", params.Seed)
 	if params.CSVFile != "" {
-		fmt.Printf("CSV output  : %s\n", params.CSVFile)
+		fmt.Printf("CSV output  : %s (%s)\n", params.CSVFile, params.StatsFormat)
+	}
+	if params.Chunked {
+		fmt.Println("Storage     : chunked")
+	}
+	if params.Compare != "" {
+		fmt.Printf("Compare     : %s\n", params.Compare)
 	}
 
 	if params.Graphics {
 		fmt.Println("Mode        : graphics")
 		RunSimulationGraphics(params)
+	} else if params.Interactive {
+		fmt.Println("Mode        : interactive")
+		RunInteractive(params)
+	} else if params.Replicates > 1 {
+		fmt.Printf("Mode        : ensemble of %d runs\n", params.Replicates)
+		RunEnsemble(params)
 	} else {
 		fmt.Println("Mode        : text")
 		RunSimulation(params)
@@ -94,58 +209,148 @@ func main() {
 }
 
 // RunSimulation executes the Wa-Tor simulation in text mode.
-// It optionally writes population statistics to a CSV file.
+// It optionally writes population statistics to a CSV file and profiles
+// of the simulation loop, and finishes by printing the time spent in each
+// phase of the run.
 func RunSimulation(p Params) {
-	world := NewWorld(p)
-
-	var csvWriter *bufio.Writer
-	var csvFile *os.File
-	var err error
+	world, err := newOcean(p)
+	if err != nil {
+		fmt.Println("Error creating world:", err)
+		os.Exit(1)
+	}
 
+	var stats *StatsWriter
 	if p.CSVFile != "" {
-		csvFile, err = os.Create(p.CSVFile)
+		var err error
+		stats, err = NewStatsWriter(p.CSVFile, p.StatsFormat, newMetadata(p))
 		if err != nil {
 			fmt.Println("Error creating CSV file:", err)
 			os.Exit(1)
 		}
-		defer csvFile.Close()
+	}
 
-		csvWriter = bufio.NewWriter(csvFile)
-		defer csvWriter.Flush()
+	var spatialWriter *bufio.Writer
+	if p.SpatialCSV != "" {
+		spatialFile, err := os.Create(p.SpatialCSV)
+		if err != nil {
+			fmt.Println("Error creating spatial CSV file:", err)
+			os.Exit(1)
+		}
+		defer spatialFile.Close()
 
-		// CSV header: step, fish count, shark count
-		fmt.Fprintln(csvWriter, "step,fish,sharks")
+		spatialWriter = bufio.NewWriter(spatialFile)
+		defer spatialWriter.Flush()
+		writeSpatialHeader(spatialWriter, p.SpatialRange)
 	}
 
-	start := time.Now()
-
-	for step := 0; step < p.Steps; step++ {
-		fish, sharks := world.Count()
+	var spatial spatialSampler
+	var loop PhaseTimes           // render and CSV time; the world times its own phases
+	renderer := NewRenderer(p, 3) // below the step and population lines
+	view := View{X: p.ViewX, Y: p.ViewY, Size: p.ViewSize}
+	stopProfiling := startProfiling(p)
 
+	res := simulate(world, p, func(step, fish, sharks int) {
 		// Log stats to CSV if requested.
-		if csvWriter != nil {
-			fmt.Fprintf(csvWriter, "%d,%d,%d\n", step, fish, sharks)
+		if stats != nil {
+			t := time.Now()
+			region := trace.StartRegion(context.Background(), "csv")
+			stats.Row(step, fish, sharks)
+			region.End()
+			loop.CSV += time.Since(t)
+		}
+
+		// Spatial pattern statistics are much more expensive than the
+		// counts, so they can be taken less often.
+		if spatialWriter != nil && step%p.SpatialEvery == 0 {
+			t := time.Now()
+			region := trace.StartRegion(context.Background(), "spatial")
+			writeSpatialRow(spatialWriter, step, spatial.stats(world, p.SpatialRange))
+			region.End()
+			loop.CSV += time.Since(t)
 		}
 
 		// Optionally print the world in ASCII.
 		if p.PrintEvery > 0 && step%p.PrintEvery == 0 {
-			clearScreen()
-			fmt.Printf("Step %d\n", step)
-			fmt.Printf("Fish=%d  Sharks=%d\n", fish, sharks)
-			world.PrintColored()
+			t := time.Now()
+			region := trace.StartRegion(context.Background(), "render")
+			if step == 0 {
+				clearScreen()
+			}
+			fmt.Printf("\033[H")
+			fmt.Printf("Step %d\033[K\n", step)
+			fmt.Printf("Fish=%d  Sharks=%d\033[K\n", fish, sharks)
+			renderer.Draw(world, view)
+			fmt.Printf("\033[%d;1H", 3+renderer.Rows()) // continue below the grid
+			region.End()
+			loop.Render += time.Since(t)
 			time.Sleep(50 * time.Millisecond) // small delay so animation is visible
 		}
+	})
+
+	stopProfiling()
+	if res.StopReason != "" {
+		fmt.Printf("\nStopped early at step %d: %s\n", res.Steps, res.StopReason)
+	}
+	if stats != nil {
+		if err := stats.Close(res); err != nil {
+			fmt.Println("Error writing CSV file:", err)
+			os.Exit(1)
+		}
+	}
+	fmt.Printf("\nSimulation finished in %v\n", res.Elapsed)
+	res.Phases.Add(loop).Print(res.Elapsed)
+}
+
+// RunResult summarises a finished simulation run.
+type RunResult struct {
+	Steps      int           // number of chronons simulated
+	StopReason string        // why the run ended early ("" = ran all p.Steps)
+	Elapsed    time.Duration // wall-clock time of the simulation loop
+	Fish       int           // fish alive at the end of the run
+	Sharks     int           // sharks alive at the end of the run
+	Phases     PhaseTimes    // time spent in the fish, shark and merge phases
+}
+
+// simulate advances the world for p.Steps chronons, sequentially or in
+// parallel according to p.Threads. Before every step it calls observe (if
+// not nil) with the step number and the current populations, which is
+// where callers print the grid or record statistics. If one of the
+// p.StopOn conditions holds, the run ends at that step and the reason is
+// returned in the result.
+func simulate(world Ocean, p Params, observe func(step, fish, sharks int)) RunResult {
+	stop, _ := newStopChecker(p) // already validated by checkParams
+	cells := world.Dim() * world.Dim()
+	res := RunResult{Steps: p.Steps}
+	start := time.Now()
+
+	for step := 0; step < p.Steps; step++ {
+		if observe != nil || stop != nil {
+			fish, sharks := world.Count()
+			if observe != nil {
+				observe(step, fish, sharks)
+			}
+			if stop != nil {
+				if reason := stop.check(fish, sharks, cells); reason != "" {
+					res.Steps, res.StopReason = step, reason
+					break
+				}
+			}
+		}
 
 		// Sequential vs parallel step.
+		region := trace.StartRegion(context.Background(), "step")
 		if p.Threads > 1 {
 			world.StepParallel(p.Threads)
 		} else {
 			world.Step()
 		}
+		region.End()
 	}
 
-	elapsed := time.Since(start)
-	fmt.Printf("\nSimulation finished in %v\n", elapsed)
+	res.Elapsed = time.Since(start)
+	res.Phases = world.PhaseTimes()
+	res.Fish, res.Sharks = world.Count()
+	return res
 }
 
 // clearScreen clears the terminal using the appropriate mechanism for the
@@ -0,0 +1,42 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+// rockMask marks the cells of a world that hold obstacles (rocks). A rock
+// is never empty, so no creature moves onto it, and it never moves. The
+// bitmap is only allocated when the first rock is placed, so worlds
+// without rocks pay nothing for it.
+type rockMask struct {
+	bits []uint64
+	size int
+}
+
+// has reports whether there is a rock at (x, y).
+func (m *rockMask) has(x, y int) bool {
+	if m.bits == nil {
+		return false
+	}
+	i := y*m.size + x
+	return m.bits[i/64]&(1<<(i%64)) != 0
+}
+
+// set places (rock = true) or removes a rock at (x, y) in a world of the
+// given size.
+func (m *rockMask) set(x, y, size int, rock bool) {
+	if m.bits == nil {
+		if !rock {
+			return
+		}
+		m.size = size
+		m.bits = make([]uint64, (size*size+63)/64)
+	}
+	i := y*m.size + x
+	if rock {
+		m.bits[i/64] |= 1 << (i % 64)
+	} else {
+		m.bits[i/64] &^= 1 << (i % 64)
+	}
+}
@@ -0,0 +1,135 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+import (
+	"runtime"
+	"sync"
+)
+
+// maxBlockSamples limits how many cells along each axis are inspected when
+// summarising one block of a downsampled view. Huge worlds are sampled on
+// a regular lattice instead of being scanned cell by cell.
+const maxBlockSamples = 8
+
+// blockCounts tallies the fish and sharks among the sampled cells of the
+// block [x0,x1) x [y0,y1) and returns how many cells were sampled.
+func blockCounts(o Ocean, x0, y0, x1, y1 int) (fish, sharks, cells int) {
+	stepX := max(1, (x1-x0)/maxBlockSamples)
+	stepY := max(1, (y1-y0)/maxBlockSamples)
+
+	for y := y0; y < y1; y += stepY {
+		for x := x0; x < x1; x += stepX {
+			switch o.CellAt(x, y) {
+			case FishCell:
+				fish++
+			case SharkCell:
+				sharks++
+			}
+			cells++
+		}
+	}
+	return
+}
+
+// blockBounds returns the range of world coordinates covered by display
+// cell i when a world of the given size is shown n cells wide.
+func blockBounds(i, n, size int) (int, int) {
+	return i * size / n, max((i+1)*size/n, i*size/n+1)
+}
+
+// majority returns the CellType that occupies most of a block with the
+// given counts; ties between fish and sharks go to the fish.
+func majority(fish, sharks, cells int) CellType {
+	empty := cells - fish - sharks
+	switch {
+	case sharks > fish && sharks > empty:
+		return SharkCell
+	case fish >= sharks && fish > empty:
+		return FishCell
+	default:
+		return Empty
+	}
+}
+
+// rasterize fills pix, an n x n RGBA pixel buffer, with an image of the
+// ocean. When n is the size of the ocean each pixel is one cell, rocks
+// included; otherwise each pixel blends the water, fish and shark colours
+// in proportion to how much of its block each one occupies, so sparse
+// populations remain visible at any scale.
+func rasterize(o Ocean, n int, pix []byte) {
+	size := o.Dim()
+	if n == size {
+		rasterizeCells(o, pix)
+		return
+	}
+	for j := 0; j < n; j++ {
+		y0, y1 := blockBounds(j, n, size)
+		for i := 0; i < n; i++ {
+			x0, x1 := blockBounds(i, n, size)
+			c := blend(blockCounts(o, x0, y0, x1, y1))
+
+			p := pix[(j*n+i)*4:]
+			p[0], p[1], p[2], p[3] = c[0], c[1], c[2], 255
+		}
+	}
+}
+
+// rasterizeCells fills pix with one pixel per cell of the ocean.
+func rasterizeCells(o Ocean, pix []byte) {
+	size := o.Dim()
+	rasterizeRegion(o, region{0, 0, size, size}, pix)
+}
+
+// region is a rectangle of w x h cells whose top-left cell is (x, y). It
+// wraps around the edges of the torus like the world does.
+type region struct{ x, y, w, h int }
+
+// rasterizeRegion fills pix, a w x h RGBA pixel buffer, with one pixel
+// per cell of region r, rocks included. Reading a million cells is
+// dominated by memory latency, so bands of rows are filled by one
+// goroutine per CPU.
+func rasterizeRegion(o Ocean, r region, pix []byte) {
+	colours := [...][3]int{Empty: waterRGB, FishCell: fishRGB, SharkCell: sharkRGB, RockCell: rockRGB}
+	size := o.Dim()
+	workers := max(1, min(runtime.NumCPU(), r.h))
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(j0, j1 int) {
+			defer wg.Done()
+			for j := j0; j < j1; j++ {
+				y := (r.y + j) % size
+				for i := 0; i < r.w; i++ {
+					c := colours[o.CellAt((r.x+i)%size, y)]
+					p := pix[(j*r.w+i)*4:]
+					p[0], p[1], p[2], p[3] = byte(c[0]), byte(c[1]), byte(c[2]), 255
+				}
+			}
+		}(w*r.h/workers, (w+1)*r.h/workers)
+	}
+	wg.Wait()
+}
+
+// Colours of water, fish, sharks and rocks in the graphical views.
+var (
+	waterRGB = [3]int{0, 10, 40}
+	fishRGB  = [3]int{0, 200, 255}
+	sharkRGB = [3]int{255, 100, 50}
+	rockRGB  = [3]int{120, 120, 120}
+)
+
+// blend mixes the water, fish and shark colours in proportion to their
+// counts in a block of cells.
+func blend(fish, sharks, cells int) [3]byte {
+	empty := cells - fish - sharks
+	var c [3]byte
+	for k := range c {
+		c[k] = byte((waterRGB[k]*empty + fishRGB[k]*fish + sharkRGB[k]*sharks) / cells)
+	}
+	return c
+}
@@ -0,0 +1,205 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+)
+
+// Colours used for the two species in every chart, matching the graphics
+// mode (cyan fish, orange sharks).
+const (
+	fishColor  = "#00a0d0"
+	sharkColor = "#e86030"
+)
+
+// Stats holds a population time series read from a stats CSV file.
+type Stats struct {
+	Step   []float64
+	Fish   []float64
+	Sharks []float64
+}
+
+// readCSVColumns reads a CSV file with a header row and returns the values
+// of the named columns as floats. Lines starting with '#' are ignored.
+func readCSVColumns(path string, names ...string) ([][]float64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.Comment = '#'
+	r.FieldsPerRecord = -1
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%s: reading header: %w", path, err)
+	}
+	index := make([]int, len(names))
+	for i, name := range names {
+		index[i] = -1
+		for j, h := range header {
+			if h == name {
+				index[i] = j
+			}
+		}
+		if index[i] < 0 {
+			return nil, fmt.Errorf("%s: missing column %q", path, name)
+		}
+	}
+
+	cols := make([][]float64, len(names))
+	for line := 2; ; line++ {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		for i, j := range index {
+			if j >= len(rec) {
+				return nil, fmt.Errorf("%s: line %d: missing column %q", path, line, names[i])
+			}
+			v, err := strconv.ParseFloat(rec[j], 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s: line %d: %w", path, line, err)
+			}
+			cols[i] = append(cols[i], v)
+		}
+	}
+	return cols, nil
+}
+
+// readStatsCSV reads a population file in the "step,fish,sharks" format
+// written by the -csv option.
+func readStatsCSV(path string) (Stats, error) {
+	cols, err := readCSVColumns(path, "step", "fish", "sharks")
+	if err != nil {
+		return Stats{}, err
+	}
+	return Stats{Step: cols[0], Fish: cols[1], Sharks: cols[2]}, nil
+}
+
+// runPlot implements the "plot" subcommand, which draws SVG figures from
+// the files written by a normal run (-csv) and by "bench":
+//
+//	population.svg  fish and sharks against step
+//	phase.svg       sharks against fish (phase portrait)
+//	runtime.svg     mean run time (with standard deviation) against threads
+//	speedup.svg     measured and ideal speedup against threads
+func runPlot(args []string) {
+	fs := flag.NewFlagSet("plot", flag.ExitOnError)
+	statsFile := fs.String("stats", "", "Population file written with -csv (CSV or .jsonl)")
+	benchFile := fs.String("bench", "", "Results CSV written by the bench subcommand")
+	outDir := fs.String("outDir", ".", "Directory for the SVG files")
+	fs.Parse(args)
+
+	if *statsFile == "" && *benchFile == "" {
+		fmt.Println("Error: plot needs -stats and/or -bench")
+		os.Exit(1)
+	}
+
+	charts := map[string]Chart{}
+
+	if *statsFile != "" {
+		s, err := readStats(*statsFile)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		charts["population.svg"] = populationChart(s)
+		charts["phase.svg"] = phaseChart(s)
+	}
+
+	if *benchFile != "" {
+		cols, err := readCSVColumns(*benchFile, "threads", "mean_s", "stddev_s", "speedup")
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		rt, sp := benchCharts(cols[0], cols[1], cols[2], cols[3])
+		charts["runtime.svg"], charts["speedup.svg"] = rt, sp
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(charts)) {
+		path := filepath.Join(*outDir, name)
+		if err := charts[name].WriteFile(path); err != nil {
+			fmt.Println("Error writing chart:", err)
+			os.Exit(1)
+		}
+		fmt.Println("Wrote", path)
+	}
+}
+
+// populationChart plots fish and shark counts against step.
+func populationChart(s Stats) Chart {
+	return Chart{
+		Title:  "Population vs step",
+		XLabel: "Step (chronon)",
+		YLabel: "Population",
+		ZeroY:  true,
+		Series: []Series{
+			{Name: "Fish", Color: fishColor, X: s.Step, Y: s.Fish},
+			{Name: "Sharks", Color: sharkColor, X: s.Step, Y: s.Sharks},
+		},
+	}
+}
+
+// phaseChart plots the shark count against the fish count, tracing the
+// predator–prey cycle.
+func phaseChart(s Stats) Chart {
+	return Chart{
+		Title:  "Phase portrait",
+		XLabel: "Fish",
+		YLabel: "Sharks",
+		Series: []Series{
+			{Name: "Trajectory", Color: "#5040a0", X: s.Fish, Y: s.Sharks},
+		},
+	}
+}
+
+// benchCharts builds the runtime and speedup charts from bench results.
+// The ideal speedup assumes perfect scaling from the first thread count.
+func benchCharts(threads, mean, stddev, speedup []float64) (Chart, Chart) {
+	runtime := Chart{
+		Title:  "Runtime vs threads",
+		XLabel: "Threads",
+		YLabel: "Time (seconds)",
+		Points: true,
+		ZeroY:  true,
+		Series: []Series{
+			{Name: "Mean ± std dev", Color: "#3070c0", X: threads, Y: mean, Err: stddev},
+		},
+	}
+
+	ideal := make([]float64, len(threads))
+	for i, t := range threads {
+		ideal[i] = t / threads[0]
+	}
+	speed := Chart{
+		Title:  "Speedup vs threads",
+		XLabel: "Threads",
+		YLabel: "Speedup",
+		Points: true,
+		ZeroY:  true,
+		Series: []Series{
+			{Name: "Measured", Color: "#3070c0", X: threads, Y: speedup},
+			{Name: "Ideal", Color: "#999999", X: threads, Y: ideal},
+		},
+	}
+	return runtime, speed
+}
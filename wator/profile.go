@@ -0,0 +1,118 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"time"
+)
+
+// PhaseTimes accumulates the wall-clock time spent in each part of a run.
+// Fish, Shark and Merge are recorded by the world while stepping; Render
+// and CSV are recorded by the simulation loop.
+type PhaseTimes struct {
+	Fish   time.Duration // moving and breeding fish
+	Shark  time.Duration // moving, feeding and breeding sharks
+	Merge  time.Duration // merging worker grids / swapping buffers
+	Render time.Duration // printing the world in text mode
+	CSV    time.Duration // writing statistics
+}
+
+// Add returns the sum of two sets of phase times.
+func (pt PhaseTimes) Add(o PhaseTimes) PhaseTimes {
+	return PhaseTimes{
+		Fish:   pt.Fish + o.Fish,
+		Shark:  pt.Shark + o.Shark,
+		Merge:  pt.Merge + o.Merge,
+		Render: pt.Render + o.Render,
+		CSV:    pt.CSV + o.CSV,
+	}
+}
+
+// Print writes a small table of the phase times and the share of the total
+// run time each one took.
+func (pt PhaseTimes) Print(total time.Duration) {
+	rows := []struct {
+		name string
+		d    time.Duration
+	}{
+		{"Fish phase", pt.Fish},
+		{"Shark phase", pt.Shark},
+		{"Merge", pt.Merge},
+		{"Render", pt.Render},
+		{"CSV", pt.CSV},
+	}
+
+	fmt.Println("Phase times:")
+	for _, r := range rows {
+		share := 0.0
+		if total > 0 {
+			share = 100 * float64(r.d) / float64(total)
+		}
+		fmt.Printf("  %-12s: %12v  (%5.1f%%)\n", r.name, r.d.Round(time.Microsecond), share)
+	}
+}
+
+// startProfiling starts the CPU profile and execution trace requested in p
+// and returns a function that stops them and writes the heap profile. The
+// caller must call it once the simulation loop has finished.
+func startProfiling(p Params) func() {
+	var cpuFile, traceFile *os.File
+
+	if p.CPUProfile != "" {
+		f, err := os.Create(p.CPUProfile)
+		if err != nil {
+			fmt.Println("Error creating CPU profile:", err)
+			os.Exit(1)
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			fmt.Println("Error starting CPU profile:", err)
+			os.Exit(1)
+		}
+		cpuFile = f
+	}
+
+	if p.TraceFile != "" {
+		f, err := os.Create(p.TraceFile)
+		if err != nil {
+			fmt.Println("Error creating trace file:", err)
+			os.Exit(1)
+		}
+		if err := trace.Start(f); err != nil {
+			fmt.Println("Error starting trace:", err)
+			os.Exit(1)
+		}
+		traceFile = f
+	}
+
+	return func() {
+		if cpuFile != nil {
+			pprof.StopCPUProfile()
+			cpuFile.Close()
+		}
+		if traceFile != nil {
+			trace.Stop()
+			traceFile.Close()
+		}
+		if p.MemProfile != "" {
+			f, err := os.Create(p.MemProfile)
+			if err != nil {
+				fmt.Println("Error creating memory profile:", err)
+				os.Exit(1)
+			}
+			defer f.Close()
+			runtime.GC() // get up-to-date statistics
+			if err := pprof.WriteHeapProfile(f); err != nil {
+				fmt.Println("Error writing memory profile:", err)
+				os.Exit(1)
+			}
+		}
+	}
+}
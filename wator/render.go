@@ -0,0 +1,139 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+)
+
+// Text rendering modes, selected with -render.
+const (
+	RenderASCII     = "ascii"     // "f", "S" and "." in 16 ANSI colours, two columns per cell
+	RenderHalfBlock = "halfblock" // "▀" in 24-bit colour, two rows of cells per character
+)
+
+// glyph is one character of the rendered grid with its colours. In ASCII
+// mode fg is an ANSI colour number; in half-block mode fg and bg are
+// 0xRRGGBB values. -1 means the terminal's default colour.
+type glyph struct {
+	ch     rune
+	fg, bg int32
+}
+
+// Renderer draws the ocean into a fixed region of the terminal. It keeps
+// the last frame and only rewrites the characters that changed, moving
+// the cursor with ANSI escape codes, and sends each frame to the terminal
+// in a single write. This avoids both the flicker of clearing the screen
+// and the cost of redrawing cells that stay the same.
+type Renderer struct {
+	mode        string // RenderASCII or RenderHalfBlock
+	blockMode   string // BlockMajority or BlockDensity
+	displaySize int    // largest overview, in blocks per side
+	top         int    // screen row (1-based) of the first line of the grid
+	out         *bufio.Writer
+
+	prev       []glyph // last frame drawn, row by row (nil = repaint everything)
+	cols, rows int     // size of the last frame in glyphs
+}
+
+// NewRenderer returns a Renderer configured by p.RenderMode, p.BlockMode
+// and p.DisplaySize that draws the grid to standard output starting at
+// screen row top.
+func NewRenderer(p Params, top int) *Renderer {
+	return &Renderer{
+		mode:        p.RenderMode,
+		blockMode:   p.BlockMode,
+		displaySize: p.DisplaySize,
+		top:         top,
+		out:         bufio.NewWriterSize(os.Stdout, 64*1024),
+	}
+}
+
+// Rows returns the number of screen rows used by the last frame.
+func (r *Renderer) Rows() int { return r.rows }
+
+// Invalidate makes the next Draw repaint every character, e.g. after the
+// screen has been cleared.
+func (r *Renderer) Invalidate() { r.prev = nil }
+
+// Draw renders the part of the ocean selected by v and updates the
+// characters that differ from the last frame.
+func (r *Renderer) Draw(o Ocean, v View) {
+	cur, cols, rows := r.frame(o, v)
+	full := len(cur) != len(r.prev) || cols != r.cols
+	width := 2 // "f " per cell in ASCII mode
+	if r.mode == RenderHalfBlock {
+		width = 1
+	}
+
+	lastY, lastX := -1, -1 // glyph the cursor is in front of
+	var fg, bg int32 = -2, -2
+	for i, g := range cur {
+		if !full && g == r.prev[i] {
+			continue
+		}
+		y, x := i/cols, i%cols
+		if y != lastY || x != lastX {
+			fmt.Fprintf(r.out, "\033[%d;%dH", r.top+y, 1+x*width)
+		}
+		if g.fg != fg || g.bg != bg {
+			r.writeColour(g)
+			fg, bg = g.fg, g.bg
+		}
+		r.out.WriteRune(g.ch)
+		if width == 2 {
+			r.out.WriteByte(' ')
+		}
+		lastY, lastX = y, x+1
+	}
+	r.out.WriteString("\033[0m")
+	r.out.Flush()
+
+	r.prev, r.cols, r.rows = cur, cols, rows
+}
+
+// writeColour writes the SGR escape code that selects the colours of g.
+func (r *Renderer) writeColour(g glyph) {
+	if r.mode != RenderHalfBlock {
+		fmt.Fprintf(r.out, "\033[%dm", g.fg)
+		return
+	}
+	fmt.Fprintf(r.out, "\033[38;2;%d;%d;%d", g.fg>>16, g.fg>>8&0xff, g.fg&0xff)
+	if g.bg < 0 {
+		r.out.WriteString(";49m")
+	} else {
+		fmt.Fprintf(r.out, ";48;2;%d;%d;%dm", g.bg>>16, g.bg>>8&0xff, g.bg&0xff)
+	}
+}
+
+// frame computes the glyphs of the next frame and its size.
+func (r *Renderer) frame(o Ocean, v View) (glyphs []glyph, cols, rows int) {
+	blocks, n := viewBlocks(o, v, r.displaySize)
+	if r.mode != RenderHalfBlock {
+		glyphs = make([]glyph, len(blocks))
+		for i, b := range blocks {
+			glyphs[i] = asciiGlyph(b, r.blockMode)
+		}
+		return glyphs, n, n
+	}
+
+	// Half blocks: the upper half of each character shows one row of
+	// blocks (foreground) and the lower half the next row (background).
+	rows = (n + 1) / 2
+	glyphs = make([]glyph, 0, n*rows)
+	for y := 0; y < n; y += 2 {
+		for x := 0; x < n; x++ {
+			g := glyph{'▀', blockRGB(blocks[y*n+x], r.blockMode), -1}
+			if y+1 < n {
+				g.bg = blockRGB(blocks[(y+1)*n+x], r.blockMode)
+			}
+			glyphs = append(glyphs, g)
+		}
+	}
+	return glyphs, n, rows
+}
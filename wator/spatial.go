@@ -0,0 +1,262 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// clusterBins is the number of size classes in a cluster histogram. Bin k
+// counts clusters of 2^k .. 2^(k+1)-1 cells; the last bin is open-ended.
+const clusterBins = 8
+
+// ClusterStats describes the connected groups (4-neighbour, on the torus)
+// of one kind of creature.
+type ClusterStats struct {
+	Count int              // number of clusters
+	Mean  float64          // mean cluster size in cells
+	Max   int              // size of the largest cluster
+	Hist  [clusterBins]int // cluster count per power-of-two size class
+}
+
+// SpatialStats holds the spatial pattern metrics of one chronon.
+type SpatialStats struct {
+	Fish, Sharks ClusterStats
+
+	// Moran's I of the fish and shark indicator fields with rook
+	// (4-neighbour) weights: > 0 clumped, ≈ 0 random, < 0 dispersed.
+	MoranFish, MoranSharks float64
+
+	// Pair correlation functions g(r) for Manhattan distances r = 1..R,
+	// fish–fish, shark–shark and fish–shark. g = 1 for a random layout,
+	// > 1 when pairs at that distance are more common than chance.
+	PCFFish, PCFSharks, PCFCross []float64
+}
+
+// kindGrid is a snapshot of the cell kinds of an n x n ocean, row by row,
+// one byte per cell so that sampling a huge chunked world stays cheap.
+type kindGrid []uint8
+
+// spatialSampler computes spatial statistics, keeping its buffers from
+// one sample to the next so that a run allocates them only once.
+type spatialSampler struct {
+	cells kindGrid
+	seen  []bool
+	stack []int
+}
+
+// stats computes the spatial metrics of the ocean, with pair correlations
+// up to distance maxR. It copies the cell kinds once and works on that
+// snapshot.
+func (s *spatialSampler) stats(o Ocean, maxR int) SpatialStats {
+	n := o.Dim()
+	if len(s.cells) != n*n {
+		s.cells = make(kindGrid, n*n)
+		s.seen = make([]bool, n*n)
+	}
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			s.cells[y*n+x] = uint8(o.CellAt(x, y))
+		}
+	}
+
+	var st SpatialStats
+	st.Fish = s.clusters(n, FishCell)
+	st.Sharks = s.clusters(n, SharkCell)
+	st.MoranFish = moransI(s.cells, n, FishCell)
+	st.MoranSharks = moransI(s.cells, n, SharkCell)
+	st.PCFFish = pairCorrelation(s.cells, n, FishCell, FishCell, maxR)
+	st.PCFSharks = pairCorrelation(s.cells, n, SharkCell, SharkCell, maxR)
+	st.PCFCross = pairCorrelation(s.cells, n, FishCell, SharkCell, maxR)
+	return st
+}
+
+// torusNeighbours returns the indices of the 4 neighbours of cell i in an
+// n x n toroidal grid stored row by row.
+func torusNeighbours(i, n int) [4]int {
+	x, y := i%n, i/n
+	return [4]int{
+		((y-1+n)%n)*n + x,
+		y*n + (x+1)%n,
+		((y+1)%n)*n + x,
+		y*n + (x-1+n)%n,
+	}
+}
+
+// clusters finds the connected components of the given kind in the
+// snapshot by flood fill.
+func (s *spatialSampler) clusters(n int, kind CellType) ClusterStats {
+	var cs ClusterStats
+	cells, seen, stack := s.cells, s.seen, s.stack
+	clear(seen)
+	k := uint8(kind)
+	total := 0
+
+	for start, c := range cells {
+		if c != k || seen[start] {
+			continue
+		}
+		size := 0
+		seen[start] = true
+		stack = append(stack[:0], start)
+		for len(stack) > 0 {
+			i := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			size++
+			for _, j := range torusNeighbours(i, n) {
+				if cells[j] == k && !seen[j] {
+					seen[j] = true
+					stack = append(stack, j)
+				}
+			}
+		}
+
+		cs.Count++
+		total += size
+		cs.Max = max(cs.Max, size)
+		bin := 0
+		for s := size; s > 1 && bin < clusterBins-1; s >>= 1 {
+			bin++
+		}
+		cs.Hist[bin]++
+	}
+	if cs.Count > 0 {
+		cs.Mean = float64(total) / float64(cs.Count)
+	}
+	s.stack = stack
+	return cs
+}
+
+// moransI returns Moran's I of the indicator field "cell holds kind" with
+// equal weights for the 4 neighbours of every cell. It is 0 when the kind
+// is absent or fills the whole grid.
+func moransI(cells kindGrid, n int, kind CellType) float64 {
+	k := uint8(kind)
+	count := 0
+	for _, c := range cells {
+		if c == k {
+			count++
+		}
+	}
+	p := float64(count) / float64(len(cells))
+
+	var num, den float64
+	for i, c := range cells {
+		zi := -p
+		if c == k {
+			zi = 1 - p
+		}
+		den += zi * zi
+		for _, j := range torusNeighbours(i, n) {
+			zj := -p
+			if cells[j] == k {
+				zj = 1 - p
+			}
+			num += zi * zj
+		}
+	}
+	if den == 0 {
+		return 0
+	}
+	// With N cells and 4 weights per cell, I = (N / 4N) * num / den.
+	return num / (4 * den)
+}
+
+// pairCorrelation returns g(r) for r = 1..maxR between cells of kind a and
+// cells of kind b, using the Manhattan distance on the torus: the number of
+// b cells at distance r from an a cell, divided by what a random layout
+// with the same density of b would give. Entries are 0 when either kind is
+// absent.
+func pairCorrelation(cells kindGrid, n int, kindA, kindB CellType, maxR int) []float64 {
+	a, b := uint8(kindA), uint8(kindB)
+	g := make([]float64, maxR)
+	countA, countB := 0, 0
+	for _, c := range cells {
+		if c == a {
+			countA++
+		}
+		if c == b {
+			countB++
+		}
+	}
+	if countA == 0 || countB == 0 {
+		return g
+	}
+
+	pairs := make([]int, maxR+1)
+	for i, c := range cells {
+		if c != a {
+			continue
+		}
+		x, y := i%n, i/n
+		for dy := -maxR; dy <= maxR; dy++ {
+			ry := maxR - abs(dy)
+			yy := ((y+dy)%n + n) % n
+			for dx := -ry; dx <= ry; dx++ {
+				r := abs(dx) + abs(dy)
+				if r == 0 {
+					continue
+				}
+				if cells[yy*n+((x+dx)%n+n)%n] == b {
+					pairs[r]++
+				}
+			}
+		}
+	}
+
+	density := float64(countB) / float64(len(cells))
+	for r := 1; r <= maxR; r++ {
+		// There are 4r cells at Manhattan distance r.
+		g[r-1] = float64(pairs[r]) / (float64(countA) * 4 * float64(r) * density)
+	}
+	return g
+}
+
+// abs returns the absolute value of x.
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// writeSpatialHeader writes the CSV header matching writeSpatialRow.
+func writeSpatialHeader(w io.Writer, maxR int) {
+	fmt.Fprint(w, "step")
+	for _, sp := range []string{"fish", "sharks"} {
+		fmt.Fprintf(w, ",%s_clusters,%s_cluster_mean,%s_cluster_max", sp, sp, sp)
+		for k := 0; k < clusterBins; k++ {
+			fmt.Fprintf(w, ",%s_size%d", sp, 1<<k)
+		}
+	}
+	fmt.Fprint(w, ",moran_fish,moran_sharks")
+	for _, pair := range []string{"ff", "ss", "fs"} {
+		for r := 1; r <= maxR; r++ {
+			fmt.Fprintf(w, ",g_%s_%d", pair, r)
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// writeSpatialRow writes the spatial metrics of one step as a CSV row.
+// The sizeN columns hold the number of clusters of N to 2N-1 cells.
+func writeSpatialRow(w io.Writer, step int, st SpatialStats) {
+	fmt.Fprintf(w, "%d", step)
+	for _, cs := range []ClusterStats{st.Fish, st.Sharks} {
+		fmt.Fprintf(w, ",%d,%.3f,%d", cs.Count, cs.Mean, cs.Max)
+		for _, h := range cs.Hist {
+			fmt.Fprintf(w, ",%d", h)
+		}
+	}
+	fmt.Fprintf(w, ",%.5f,%.5f", st.MoranFish, st.MoranSharks)
+	for _, g := range [][]float64{st.PCFFish, st.PCFSharks, st.PCFCross} {
+		for _, v := range g {
+			fmt.Fprintf(w, ",%.4f", v)
+		}
+	}
+	fmt.Fprintln(w)
+}
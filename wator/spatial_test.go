@@ -0,0 +1,194 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// gridOf builds a square kindGrid from rows of 'f' (fish), 'S' (shark)
+// and '.' (empty), and returns it with its side.
+func gridOf(rows ...string) (kindGrid, int) {
+	cells := kindGrid(strings.Join(rows, ""))
+	for i, c := range cells {
+		switch c {
+		case 'f':
+			cells[i] = uint8(FishCell)
+		case 'S':
+			cells[i] = uint8(SharkCell)
+		default:
+			cells[i] = uint8(Empty)
+		}
+	}
+	return cells, len(rows)
+}
+
+// checkerboard returns an n x n grid with fish on the cells where x + y
+// is even and sharks on the others.
+func checkerboard(n int) kindGrid {
+	cells := make(kindGrid, n*n)
+	for i := range cells {
+		if (i%n+i/n)%2 == 0 {
+			cells[i] = uint8(FishCell)
+		} else {
+			cells[i] = uint8(SharkCell)
+		}
+	}
+	return cells
+}
+
+// randomGrid returns an n x n grid where each cell holds a fish with
+// probability pf, otherwise a shark with probability ps.
+func randomGrid(n int, pf, ps float64, seed int64) kindGrid {
+	rng := rand.New(rand.NewSource(seed))
+	cells := make(kindGrid, n*n)
+	for i := range cells {
+		switch r := rng.Float64(); {
+		case r < pf:
+			cells[i] = uint8(FishCell)
+		case r < pf+ps:
+			cells[i] = uint8(SharkCell)
+		}
+	}
+	return cells
+}
+
+// TestClusters checks the cluster counts, sizes and histogram on grids
+// with known clusters.
+func TestClusters(t *testing.T) {
+	// The fish in three corners join up across both edges of the torus
+	// with the one next to them into a cluster of 4; the fish in the
+	// middle is on its own. The sharks join across the left and right
+	// edges.
+	wrapped, n := gridOf(
+		"f...ff",
+		"......",
+		"..f...",
+		"S....S",
+		"......",
+		"f.....",
+	)
+	tests := []struct {
+		name  string
+		cells kindGrid
+		n     int
+		kind  CellType
+		want  ClusterStats
+	}{
+		{"none", make(kindGrid, 16), 4, FishCell, ClusterStats{}},
+		{"checkerboard", checkerboard(8), 8, FishCell,
+			ClusterStats{Count: 32, Mean: 1, Max: 1, Hist: [clusterBins]int{0: 32}}},
+		{"single cell", checkerboard(1), 1, FishCell,
+			ClusterStats{Count: 1, Mean: 1, Max: 1, Hist: [clusterBins]int{0: 1}}},
+		{"wrapped fish", wrapped, n, FishCell,
+			ClusterStats{Count: 2, Mean: 2.5, Max: 4, Hist: [clusterBins]int{0: 1, 2: 1}}},
+		{"wrapped sharks", wrapped, n, SharkCell,
+			ClusterStats{Count: 1, Mean: 2, Max: 2, Hist: [clusterBins]int{1: 1}}},
+		{"one big cluster", make(kindGrid, 400), 20, Empty,
+			ClusterStats{Count: 1, Mean: 400, Max: 400, Hist: [clusterBins]int{7: 1}}},
+	}
+	for _, tt := range tests {
+		if got := samplerOf(tt.cells).clusters(tt.n, tt.kind); got != tt.want {
+			t.Errorf("%s: clusters = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// samplerOf returns a spatialSampler holding cells, as stats leaves it.
+func samplerOf(cells kindGrid) *spatialSampler {
+	return &spatialSampler{cells: cells, seen: make([]bool, len(cells))}
+}
+
+// TestMoransI checks Moran's I on a perfectly dispersed, a clumped, a
+// random and a uniform layout.
+func TestMoransI(t *testing.T) {
+	halves := make(kindGrid, 20*20)
+	for i := range halves[:200] {
+		halves[i] = uint8(FishCell)
+	}
+	tests := []struct {
+		name   string
+		cells  kindGrid
+		n      int
+		want   float64
+		margin float64
+	}{
+		{"checkerboard", checkerboard(8), 8, -1, 1e-12},
+		// The halves meet along two lines of 20 cells on the torus, so
+		// 80 of the 1600 ordered neighbour pairs differ:
+		// I = (1520 - 80) / 1600.
+		{"two halves", halves, 20, 0.9, 1e-12},
+		{"random", randomGrid(200, 0.3, 0, 1), 200, 0, 0.02},
+		{"empty grid", make(kindGrid, 25), 5, 0, 0},
+		{"no fish", randomGrid(10, 0, 0.5, 2), 10, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := moransI(tt.cells, tt.n, FishCell); math.Abs(got-tt.want) > tt.margin {
+			t.Errorf("%s: Moran's I = %g, want %g ± %g", tt.name, got, tt.want, tt.margin)
+		}
+	}
+}
+
+// TestPairCorrelation checks g(r) on layouts where it is known: a
+// checkerboard, where cells of one colour are only at even distances, and
+// uniform layouts, where g(r) is 1 at every distance.
+func TestPairCorrelation(t *testing.T) {
+	full := make(kindGrid, 9*9)
+	for i := range full {
+		full[i] = uint8(SharkCell)
+	}
+	tests := []struct {
+		name         string
+		cells        kindGrid
+		n            int
+		kindA, kindB CellType
+		want         []float64
+		margin       float64
+	}{
+		// Half the cells are fish, so all 4r cells at an even
+		// distance being fish is twice the random count.
+		{"checkerboard fish-fish", checkerboard(10), 10, FishCell, FishCell, []float64{0, 2, 0, 2}, 1e-12},
+		{"checkerboard fish-shark", checkerboard(10), 10, FishCell, SharkCell, []float64{2, 0, 2, 0}, 1e-12},
+		{"full grid", full, 9, SharkCell, SharkCell, []float64{1, 1, 1, 1}, 1e-12},
+		{"random fish-fish", randomGrid(200, 0.3, 0.1, 3), 200, FishCell, FishCell, []float64{1, 1, 1, 1, 1}, 0.03},
+		{"random fish-shark", randomGrid(200, 0.3, 0.1, 3), 200, FishCell, SharkCell, []float64{1, 1, 1, 1, 1}, 0.03},
+		{"no sharks", randomGrid(10, 0.5, 0, 4), 10, FishCell, SharkCell, []float64{0, 0, 0}, 0},
+	}
+	for _, tt := range tests {
+		got := pairCorrelation(tt.cells, tt.n, tt.kindA, tt.kindB, len(tt.want))
+		for r := range got {
+			if math.Abs(got[r]-tt.want[r]) > tt.margin {
+				t.Errorf("%s: g = %.3f, want %v ± %g", tt.name, got, tt.want, tt.margin)
+				break
+			}
+		}
+	}
+}
+
+// TestSpatialSamplerReuse checks that a sampler gives the same statistics
+// as a new one when it is reused, for the same world and another size.
+func TestSpatialSamplerReuse(t *testing.T) {
+	p := Params{FishBreed: 3, SharkBreed: 5, Starve: 3, Seed: 5}
+	var reused spatialSampler
+	for _, size := range []int{30, 30, 12} {
+		p.GridSize, p.NumFish, p.NumShark = size, size*size/3, size*size/15
+		world, err := NewWorld(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 3; i++ {
+			world.Step()
+		}
+		var fresh spatialSampler
+		if got, want := reused.stats(world, 5), fresh.stats(world, 5); !reflect.DeepEqual(got, want) {
+			t.Errorf("size %d: reused sampler %+v, want %+v", size, got, want)
+		}
+	}
+}
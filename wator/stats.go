@@ -0,0 +1,218 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Version identifies the program in the metadata of statistics files.
+const Version = "1.1.0"
+
+// Output formats for the -csv statistics file.
+const (
+	FormatCSV   = "csv"   // "step,fish,sharks" with a commented metadata header
+	FormatJSONL = "jsonl" // one JSON object per line
+)
+
+// Metadata describes the run that produced a statistics file, so the file
+// can be interpreted and reproduced without the command line.
+type Metadata struct {
+	Version   string    `json:"version"`
+	GoVersion string    `json:"go_version"`
+	Start     time.Time `json:"start"`
+	Params    Params    `json:"params"`
+
+	// Replicates describes how each run of an ensemble ended.
+	Replicates []ReplicateInfo `json:"replicates,omitempty"`
+}
+
+// ReplicateInfo records the seed of one run of an ensemble and how it
+// ended: StopStep is the step a -stopOn condition held at and StopReason
+// that condition, or the number of steps and "" if it ran to the end.
+type ReplicateInfo struct {
+	Seed       int64  `json:"seed"`
+	StopStep   int    `json:"stop_step"`
+	StopReason string `json:"stop_reason"`
+}
+
+// newMetadata returns the metadata for a run of p starting now.
+func newMetadata(p Params) Metadata {
+	return Metadata{
+		Version:   Version,
+		GoVersion: runtime.Version(),
+		Start:     time.Now(),
+		Params:    p,
+	}
+}
+
+// writeCSVMetadata writes the metadata as "# key: value" comment lines,
+// which readers of the CSV (and the plot and analyze subcommands) skip.
+func writeCSVMetadata(w io.Writer, m Metadata) {
+	fmt.Fprintf(w, "# wator %s (%s)\n", m.Version, m.GoVersion)
+	fmt.Fprintf(w, "# start: %s\n", m.Start.Format(time.RFC3339))
+
+	v := reflect.ValueOf(m.Params)
+	for i := 0; i < v.NumField(); i++ {
+		fmt.Fprintf(w, "# %s: %v\n", v.Type().Field(i).Name, v.Field(i).Interface())
+	}
+	for r, info := range m.Replicates {
+		reason := info.StopReason
+		if reason == "" {
+			reason = "none"
+		}
+		fmt.Fprintf(w, "# replicate %d: seed=%d stop_step=%d stop_reason=%s\n", r, info.Seed, info.StopStep, reason)
+	}
+}
+
+// StatsWriter writes the population of every step to the -csv file in
+// the format chosen with -statsFormat.
+type StatsWriter struct {
+	w      *bufio.Writer
+	file   *os.File
+	format string
+}
+
+// jsonMeta is the first line of a JSON Lines statistics file.
+type jsonMeta struct {
+	Meta Metadata `json:"meta"`
+}
+
+// jsonRow is one step of a JSON Lines statistics file.
+type jsonRow struct {
+	Step   int `json:"step"`
+	Fish   int `json:"fish"`
+	Sharks int `json:"sharks"`
+}
+
+// jsonSet records a parameter changed during a run.
+type jsonSet struct {
+	Set struct {
+		Step  int    `json:"step"`
+		Name  string `json:"name"`
+		Value any    `json:"value"`
+	} `json:"set"`
+}
+
+// jsonEnd is the last line of a JSON Lines statistics file.
+type jsonEnd struct {
+	End struct {
+		Steps      int     `json:"steps"`
+		StopReason string  `json:"stop_reason,omitempty"`
+		ElapsedSec float64 `json:"elapsed_s"`
+	} `json:"end"`
+}
+
+// NewStatsWriter creates the statistics file and writes its header: the
+// commented metadata and column names for CSV, or a {"meta": ...} object
+// for JSON Lines.
+func NewStatsWriter(path, format string, m Metadata) (*StatsWriter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	sw := &StatsWriter{w: bufio.NewWriter(f), file: f, format: format}
+
+	if format == FormatJSONL {
+		sw.writeJSON(jsonMeta{m})
+	} else {
+		writeCSVMetadata(sw.w, m)
+		fmt.Fprintln(sw.w, "step,fish,sharks")
+	}
+	return sw, nil
+}
+
+// writeJSON writes v as one line of JSON.
+func (sw *StatsWriter) writeJSON(v any) {
+	b, _ := json.Marshal(v)
+	sw.w.Write(b)
+	sw.w.WriteByte('\n')
+}
+
+// Row records the populations at one step.
+func (sw *StatsWriter) Row(step, fish, sharks int) {
+	if sw.format == FormatJSONL {
+		sw.writeJSON(jsonRow{step, fish, sharks})
+		return
+	}
+	fmt.Fprintf(sw.w, "%d,%d,%d\n", step, fish, sharks)
+}
+
+// Set records that parameter name (its flag name) was changed to value
+// at step, e.g. from the tuning panel of the graphics window. In CSV it
+// is a "# set: step=120 starve=5" comment line, which readers skip.
+func (sw *StatsWriter) Set(step int, name string, value any) {
+	if sw.format == FormatJSONL {
+		var set jsonSet
+		set.Set.Step, set.Set.Name, set.Set.Value = step, name, value
+		sw.writeJSON(set)
+		return
+	}
+	fmt.Fprintf(sw.w, "# set: step=%d %s=%v\n", step, name, value)
+}
+
+// Close records how the run ended and closes the file.
+func (sw *StatsWriter) Close(res RunResult) error {
+	if sw.format == FormatJSONL {
+		var end jsonEnd
+		end.End.Steps = res.Steps
+		end.End.StopReason = res.StopReason
+		end.End.ElapsedSec = res.Elapsed.Seconds()
+		sw.writeJSON(end)
+	} else {
+		if res.StopReason != "" {
+			fmt.Fprintf(sw.w, "# stopped: %s at step %d\n", res.StopReason, res.Steps)
+		}
+		fmt.Fprintf(sw.w, "# steps: %d\n", res.Steps)
+		fmt.Fprintf(sw.w, "# elapsed: %v\n", res.Elapsed)
+	}
+
+	if err := sw.w.Flush(); err != nil {
+		sw.file.Close()
+		return err
+	}
+	return sw.file.Close()
+}
+
+// readStats reads a population file written with -csv, in either format.
+// JSON Lines files are recognised by their .jsonl extension.
+func readStats(path string) (Stats, error) {
+	if !strings.HasSuffix(path, "."+FormatJSONL) {
+		return readStatsCSV(path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return Stats{}, err
+	}
+	defer f.Close()
+
+	var s Stats
+	sc := bufio.NewScanner(f)
+	for line := 1; sc.Scan(); line++ {
+		var row struct {
+			Step, Fish, Sharks *int
+		}
+		if err := json.Unmarshal(sc.Bytes(), &row); err != nil {
+			return Stats{}, fmt.Errorf("%s: line %d: %w", path, line, err)
+		}
+		if row.Step == nil || row.Fish == nil || row.Sharks == nil {
+			continue // metadata, set or end line
+		}
+		s.Step = append(s.Step, float64(*row.Step))
+		s.Fish = append(s.Fish, float64(*row.Fish))
+		s.Sharks = append(s.Sharks, float64(*row.Sharks))
+	}
+	return s, sc.Err()
+}
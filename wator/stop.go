@@ -0,0 +1,95 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Reasons reported when a run ends before p.Steps chronons.
+const (
+	StopExtinct    = "extinct"    // fish or sharks have died out
+	StopSaturated  = "saturated"  // every cell is occupied
+	StopStationary = "stationary" // both populations have stopped changing
+	StopQuit       = "quit"       // the user ended an interactive run
+)
+
+// stopChecker decides when a run can end early. It is configured from
+// Params.StopOn and fed the populations before every step.
+type stopChecker struct {
+	extinct    bool
+	saturated  bool
+	stationary bool
+	window     int     // steps the populations must stay flat
+	tol        float64 // allowed relative spread within the window
+
+	fish, sharks []int // ring buffers of the last window counts
+	seen         int   // number of counts added so far
+}
+
+// newStopChecker parses p.StopOn, a comma-separated list of stop
+// conditions. It returns nil if no condition is enabled.
+func newStopChecker(p Params) (*stopChecker, error) {
+	if strings.TrimSpace(p.StopOn) == "" {
+		return nil, nil
+	}
+
+	s := &stopChecker{window: p.StationaryWindow, tol: p.StationaryTol}
+	for _, name := range strings.Split(p.StopOn, ",") {
+		switch strings.TrimSpace(name) {
+		case StopExtinct:
+			s.extinct = true
+		case StopSaturated:
+			s.saturated = true
+		case StopStationary:
+			s.stationary = true
+		default:
+			return nil, fmt.Errorf("unknown stop condition %q (want %s, %s or %s)",
+				name, StopExtinct, StopSaturated, StopStationary)
+		}
+	}
+	if s.stationary {
+		if s.window < 2 || s.tol < 0 {
+			return nil, fmt.Errorf("stationary stop needs stationaryWindow >= 2 and stationaryTol >= 0")
+		}
+		s.fish = make([]int, s.window)
+		s.sharks = make([]int, s.window)
+	}
+	return s, nil
+}
+
+// check records the current populations and returns the reason the run
+// should stop, or "" to carry on.
+func (s *stopChecker) check(fish, sharks, cells int) string {
+	if s.extinct && (fish == 0 || sharks == 0) {
+		return StopExtinct
+	}
+	if s.saturated && fish+sharks >= cells {
+		return StopSaturated
+	}
+	if s.stationary {
+		s.fish[s.seen%s.window] = fish
+		s.sharks[s.seen%s.window] = sharks
+		s.seen++
+		if s.seen >= s.window && flat(s.fish, s.tol) && flat(s.sharks, s.tol) {
+			return StopStationary
+		}
+	}
+	return ""
+}
+
+// flat reports whether the spread (max - min) of counts is within tol
+// times their mean.
+func flat(counts []int, tol float64) bool {
+	lo, hi, sum := counts[0], counts[0], 0
+	for _, c := range counts {
+		lo, hi = min(lo, c), max(hi, c)
+		sum += c
+	}
+	m := float64(sum) / float64(len(counts))
+	return float64(hi-lo) <= tol*m
+}
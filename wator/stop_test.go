@@ -0,0 +1,140 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestNewStopChecker checks the parsing of -stopOn and its stationary
+// settings.
+func TestNewStopChecker(t *testing.T) {
+	tests := []struct {
+		stopOn                         string
+		window                         int
+		tol                            float64
+		extinct, saturated, stationary bool
+		wantErr                        string // part of the error ("" = none)
+	}{
+		{"", 10, 0.1, false, false, false, ""},
+		{"  ", 10, 0.1, false, false, false, ""},
+		{"extinct", 10, 0.1, true, false, false, ""},
+		{"saturated", 10, 0.1, false, true, false, ""},
+		{" extinct , stationary ", 10, 0.1, true, false, true, ""},
+		{"extinct,saturated,stationary", 2, 0, true, true, true, ""},
+		{"extinct,extinct", 10, 0.1, true, false, false, ""},
+		{"Extinct", 10, 0.1, false, false, false, `unknown stop condition "Extinct"`},
+		{"extinct,", 10, 0.1, false, false, false, `unknown stop condition ""`},
+		{"quit", 10, 0.1, false, false, false, `unknown stop condition "quit"`},
+		{"stationary", 1, 0.1, false, false, false, "stationaryWindow >= 2"},
+		{"stationary", 10, -0.5, false, false, false, "stationaryTol >= 0"},
+		{"extinct", 1, -0.5, true, false, false, ""}, // the window only matters for stationary
+	}
+	for _, tt := range tests {
+		s, err := newStopChecker(Params{StopOn: tt.stopOn, StationaryWindow: tt.window, StationaryTol: tt.tol})
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%q: error %v, want %q", tt.stopOn, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", tt.stopOn, err)
+			continue
+		}
+		if !tt.extinct && !tt.saturated && !tt.stationary {
+			if s != nil {
+				t.Errorf("%q: got a checker, want nil", tt.stopOn)
+			}
+			continue
+		}
+		if s == nil || s.extinct != tt.extinct || s.saturated != tt.saturated || s.stationary != tt.stationary {
+			t.Errorf("%q: checker %+v, want extinct %v, saturated %v, stationary %v",
+				tt.stopOn, s, tt.extinct, tt.saturated, tt.stationary)
+		}
+	}
+}
+
+// TestStopCheck feeds populations to a checker and checks the step and
+// reason it stops at.
+func TestStopCheck(t *testing.T) {
+	type counts struct{ fish, sharks int }
+	const cells = 100
+	tests := []struct {
+		name     string
+		stopOn   string
+		window   int
+		tol      float64
+		steps    []counts
+		wantStep int // -1 = never stops
+		want     string
+	}{
+		{"fish die out", "extinct", 0, 0,
+			[]counts{{50, 10}, {20, 30}, {0, 40}}, 2, StopExtinct},
+		{"sharks die out", "extinct", 0, 0,
+			[]counts{{50, 1}, {60, 0}}, 1, StopExtinct},
+		{"extinct at the start", "extinct", 0, 0,
+			[]counts{{50, 0}}, 0, StopExtinct},
+		{"both alive", "extinct", 0, 0,
+			[]counts{{1, 1}, {99, 1}}, -1, ""},
+		{"grid full", "saturated", 0, 0,
+			[]counts{{80, 10}, {95, 5}}, 1, StopSaturated},
+		{"extinct before saturated", "saturated,extinct", 0, 0,
+			[]counts{{100, 0}}, 0, StopExtinct},
+		{"extinction not asked for", "saturated", 0, 0,
+			[]counts{{50, 0}, {0, 0}}, -1, ""},
+		{"flat from the start", "stationary", 3, 0,
+			[]counts{{50, 10}, {50, 10}, {50, 10}}, 2, StopStationary},
+		{"flat after a change", "stationary", 3, 0,
+			[]counts{{40, 10}, {50, 10}, {50, 10}, {50, 10}}, 3, StopStationary},
+		{"within tolerance", "stationary", 2, 0.1,
+			[]counts{{100, 20}, {105, 21}}, 1, StopStationary},
+		{"outside tolerance", "stationary", 2, 0.1,
+			[]counts{{100, 20}, {100, 23}, {100, 20}}, -1, ""},
+		{"oscillating", "stationary", 4, 0.05,
+			[]counts{{50, 10}, {60, 12}, {50, 10}, {60, 12}, {50, 10}, {60, 12}}, -1, ""},
+		{"window wraps", "stationary", 2, 0,
+			[]counts{{1, 1}, {2, 2}, {3, 3}, {3, 3}}, 3, StopStationary},
+	}
+	for _, tt := range tests {
+		s, err := newStopChecker(Params{StopOn: tt.stopOn, StationaryWindow: tt.window, StationaryTol: tt.tol})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		step, reason := -1, ""
+		for i, c := range tt.steps {
+			if reason = s.check(c.fish, c.sharks, cells); reason != "" {
+				step = i
+				break
+			}
+		}
+		if step != tt.wantStep || reason != tt.want {
+			t.Errorf("%s: stopped at step %d (%q), want %d (%q)", tt.name, step, reason, tt.wantStep, tt.want)
+		}
+	}
+}
+
+// TestFlat checks the spread test of the stationary stop.
+func TestFlat(t *testing.T) {
+	tests := []struct {
+		counts []int
+		tol    float64
+		want   bool
+	}{
+		{[]int{5, 5, 5}, 0, true},
+		{[]int{5, 6, 5}, 0, false},
+		{[]int{90, 100, 110}, 0.2, true},
+		{[]int{90, 100, 111}, 0.2, false},
+		{[]int{0, 0}, 0, true}, // extinct populations are flat
+		{[]int{0, 1}, 10, true},
+	}
+	for _, tt := range tests {
+		if got := flat(tt.counts, tt.tol); got != tt.want {
+			t.Errorf("flat(%v, %g) = %v, want %v", tt.counts, tt.tol, got, tt.want)
+		}
+	}
+}
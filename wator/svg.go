@@ -0,0 +1,186 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"os"
+	"strings"
+)
+
+// Chart dimensions and margins in SVG user units (pixels).
+const (
+	chartWidth  = 760
+	chartHeight = 460
+	marginLeft  = 80
+	marginRight = 150
+	marginTop   = 50
+	marginBot   = 60
+)
+
+// Series is one line on a Chart. Err, if set, holds a symmetric error bar
+// for every point.
+type Series struct {
+	Name  string
+	Color string
+	X, Y  []float64
+	Err   []float64
+}
+
+// Chart is a simple line chart that can be written out as a standalone
+// SVG file. It is used for the population, phase-portrait and speedup
+// figures so that they can be regenerated without any external tools.
+type Chart struct {
+	Title  string
+	XLabel string
+	YLabel string
+	Series []Series
+	Points bool // draw a marker at every data point
+	ZeroY  bool // always include y = 0 on the vertical axis
+}
+
+// bounds returns the data range covered by all series, including error bars.
+func (c Chart) bounds() (x0, x1, y0, y1 float64) {
+	x0, y0 = math.Inf(1), math.Inf(1)
+	x1, y1 = math.Inf(-1), math.Inf(-1)
+	for _, s := range c.Series {
+		for i := range s.X {
+			e := 0.0
+			if s.Err != nil {
+				e = s.Err[i]
+			}
+			x0, x1 = math.Min(x0, s.X[i]), math.Max(x1, s.X[i])
+			y0, y1 = math.Min(y0, s.Y[i]-e), math.Max(y1, s.Y[i]+e)
+		}
+	}
+	if c.ZeroY {
+		y0 = math.Min(y0, 0)
+	}
+	if math.IsInf(x0, 0) {
+		return 0, 1, 0, 1
+	}
+	if x1 == x0 {
+		x1 = x0 + 1
+	}
+	if y1 == y0 {
+		y1 = y0 + 1
+	}
+	return
+}
+
+// niceTicks returns roughly n evenly spaced, round tick values covering
+// [lo, hi], together with the extended range they span.
+func niceTicks(lo, hi float64, n int) ([]float64, float64, float64) {
+	step := niceNum((hi - lo) / float64(n))
+	lo = math.Floor(lo/step) * step
+	hi = math.Ceil(hi/step) * step
+
+	var ticks []float64
+	for v := lo; v <= hi+step/2; v += step {
+		ticks = append(ticks, v)
+	}
+	return ticks, lo, hi
+}
+
+// niceNum rounds x to 1, 2 or 5 times a power of ten.
+func niceNum(x float64) float64 {
+	exp := math.Floor(math.Log10(x))
+	f := x / math.Pow(10, exp)
+	switch {
+	case f < 1.5:
+		f = 1
+	case f < 3:
+		f = 2
+	case f < 7:
+		f = 5
+	default:
+		f = 10
+	}
+	return f * math.Pow(10, exp)
+}
+
+// formatTick prints a tick label without needless decimals.
+func formatTick(v float64) string {
+	if math.Abs(v) >= 1e6 {
+		return fmt.Sprintf("%.3g", v)
+	}
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.3f", v), "0"), ".")
+}
+
+// SVG renders the chart as a complete SVG document.
+func (c Chart) SVG() string {
+	var b strings.Builder
+	x0, x1, y0, y1 := c.bounds()
+	xTicks, x0, x1 := niceTicks(x0, x1, 8)
+	yTicks, y0, y1 := niceTicks(y0, y1, 6)
+
+	plotW := float64(chartWidth - marginLeft - marginRight)
+	plotH := float64(chartHeight - marginTop - marginBot)
+	px := func(x float64) float64 { return marginLeft + (x-x0)/(x1-x0)*plotW }
+	py := func(y float64) float64 { return marginTop + plotH - (y-y0)/(y1-y0)*plotH }
+
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n",
+		chartWidth, chartHeight, chartWidth, chartHeight)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="white"/>`+"\n")
+	fmt.Fprintf(&b, `<text x="%d" y="28" text-anchor="middle" font-size="16" font-weight="bold">%s</text>`+"\n",
+		marginLeft+int(plotW)/2, html.EscapeString(c.Title))
+
+	// Grid lines and tick labels.
+	for _, t := range xTicks {
+		x := px(t)
+		fmt.Fprintf(&b, `<line x1="%.1f" y1="%d" x2="%.1f" y2="%.1f" stroke="#ddd"/>`+"\n", x, marginTop, x, marginTop+plotH)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="middle">%s</text>`+"\n", x, marginTop+plotH+18, formatTick(t))
+	}
+	for _, t := range yTicks {
+		y := py(t)
+		fmt.Fprintf(&b, `<line x1="%d" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#ddd"/>`+"\n", marginLeft, y, marginLeft+plotW, y)
+		fmt.Fprintf(&b, `<text x="%d" y="%.1f" text-anchor="end">%s</text>`+"\n", marginLeft-6, y+4, formatTick(t))
+	}
+	fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%.1f" height="%.1f" fill="none" stroke="black"/>`+"\n",
+		marginLeft, marginTop, plotW, plotH)
+
+	// Axis labels.
+	fmt.Fprintf(&b, `<text x="%.1f" y="%d" text-anchor="middle">%s</text>`+"\n",
+		marginLeft+plotW/2, chartHeight-15, html.EscapeString(c.XLabel))
+	fmt.Fprintf(&b, `<text x="20" y="%.1f" text-anchor="middle" transform="rotate(-90 20 %.1f)">%s</text>`+"\n",
+		marginTop+plotH/2, marginTop+plotH/2, html.EscapeString(c.YLabel))
+
+	// Data.
+	for i, s := range c.Series {
+		var pts strings.Builder
+		for j := range s.X {
+			fmt.Fprintf(&pts, "%.1f,%.1f ", px(s.X[j]), py(s.Y[j]))
+		}
+		fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="1.5"/>`+"\n",
+			strings.TrimSpace(pts.String()), s.Color)
+
+		for j := range s.X {
+			if s.Err != nil && s.Err[j] > 0 {
+				fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s"/>`+"\n",
+					px(s.X[j]), py(s.Y[j]-s.Err[j]), px(s.X[j]), py(s.Y[j]+s.Err[j]), s.Color)
+			}
+			if c.Points {
+				fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="3.5" fill="%s"/>`+"\n", px(s.X[j]), py(s.Y[j]), s.Color)
+			}
+		}
+
+		// Legend entry.
+		ly := marginTop + 10 + 20*i
+		fmt.Fprintf(&b, `<line x1="%.1f" y1="%d" x2="%.1f" y2="%d" stroke="%s" stroke-width="3"/>`+"\n",
+			marginLeft+plotW+12, ly, marginLeft+plotW+32, ly, s.Color)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%d">%s</text>`+"\n", marginLeft+plotW+38, ly+4, html.EscapeString(s.Name))
+	}
+
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// WriteFile writes the chart as an SVG file.
+func (c Chart) WriteFile(path string) error {
+	return os.WriteFile(path, []byte(c.SVG()), 0o644)
+}
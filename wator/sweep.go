@@ -0,0 +1,311 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// sweepAxis is one parameter varied by the sweep: a flag name such as
+// "starve" and the values it takes.
+type sweepAxis struct {
+	name   string
+	values []string
+}
+
+// parseSweepAxis parses a -vary argument of the form name=spec, where spec
+// is a list ("2,3,5"), an inclusive range ("2:6") or a range with a step
+// ("2:10:2").
+func parseSweepAxis(arg string) (sweepAxis, error) {
+	name, spec, ok := strings.Cut(arg, "=")
+	if !ok || name == "" || spec == "" {
+		return sweepAxis{}, fmt.Errorf("expected name=values, got %q", arg)
+	}
+	if name == "seed" {
+		// Every run gets its own seed from -seed and -seeds, which would
+		// overwrite the swept values.
+		return sweepAxis{}, fmt.Errorf("seed cannot be varied; use -seed and -seeds instead")
+	}
+
+	axis := sweepAxis{name: name}
+	if !strings.Contains(spec, ":") {
+		for _, v := range strings.Split(spec, ",") {
+			axis.values = append(axis.values, strings.TrimSpace(v))
+		}
+		return axis, nil
+	}
+
+	parts := strings.Split(spec, ":")
+	nums := make([]int, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || len(parts) > 3 {
+			return sweepAxis{}, fmt.Errorf("bad range %q (want from:to or from:to:step)", spec)
+		}
+		nums[i] = n
+	}
+	step := 1
+	if len(nums) == 3 {
+		step = nums[2]
+	}
+	if step <= 0 || nums[1] < nums[0] {
+		return sweepAxis{}, fmt.Errorf("bad range %q", spec)
+	}
+	for v := nums[0]; v <= nums[1]; v += step {
+		axis.values = append(axis.values, strconv.Itoa(v))
+	}
+	return axis, nil
+}
+
+// paramsWith returns the default Params with the given flag values applied,
+// using the command-line names (e.g. {"starve": "5"}), so that any field
+// can be set from a string.
+func paramsWith(values map[string]string) (Params, error) {
+	fs := flag.NewFlagSet("params", flag.ContinueOnError)
+	p := Params{}
+	registerParamFlags(fs, &p)
+	for name, v := range values {
+		if fs.Lookup(name) == nil {
+			return Params{}, fmt.Errorf("unknown parameter %q", name)
+		}
+		if err := fs.Set(name, v); err != nil {
+			return Params{}, fmt.Errorf("parameter %s: %w", name, err)
+		}
+	}
+	return p, nil
+}
+
+// sweepRun is one simulation of the sweep: a parameter combination (by
+// index into the axes' values) and a seed.
+type sweepRun struct {
+	combo  []int
+	params Params
+}
+
+// SweepSummary summarises one finished sweep run.
+type SweepSummary struct {
+	Extinct        string  // "none", "fish", "sharks" or "both"
+	ExtinctionStep int     // first step at which a species had died out (-1 = never)
+	MeanFish       float64 // mean fish population over the run
+	MeanSharks     float64 // mean shark population over the run
+	Period         int     // dominant oscillation period of the fish population (0 = none)
+	Steps          int     // chronons simulated
+	StopReason     string  // why the run ended early ("" = ran all steps)
+}
+
+// summariseRun runs p headless and summarises the population series.
+func summariseRun(p Params) (SweepSummary, error) {
+	world, err := newOcean(p)
+	if err != nil {
+		return SweepSummary{}, err
+	}
+	fish := make([]float64, 0, p.Steps)
+	sharks := make([]float64, 0, p.Steps)
+	sum := SweepSummary{Extinct: "none", ExtinctionStep: -1}
+
+	res := simulate(world, p, func(step, f, s int) {
+		fish = append(fish, float64(f))
+		sharks = append(sharks, float64(s))
+		if sum.ExtinctionStep < 0 && (f == 0 || s == 0) {
+			sum.ExtinctionStep = step
+		}
+	})
+
+	sum.Steps, sum.StopReason = res.Steps, res.StopReason
+	sum.MeanFish = mean(fish)
+	sum.MeanSharks = mean(sharks)
+	sum.Period = dominantPeriod(fish[len(fish)/10:]) // skip the initial transient
+
+	// The series holds the populations before each step; the final ones
+	// also count a species that died out in the last step.
+	if sum.ExtinctionStep < 0 && (res.Fish == 0 || res.Sharks == 0) {
+		sum.ExtinctionStep = res.Steps
+	}
+	switch {
+	case res.Fish == 0 && res.Sharks == 0:
+		sum.Extinct = "both"
+	case res.Fish == 0:
+		sum.Extinct = "fish"
+	case res.Sharks == 0:
+		sum.Extinct = "sharks"
+	}
+	return sum, nil
+}
+
+// runSweep implements the "sweep" subcommand. Every combination of the
+// -vary values is run with -seeds different seeds, spread over a pool of
+// -workers independent headless simulations, and one summary row per run
+// is written to -out.
+func runSweep(args []string) {
+	fs := flag.NewFlagSet("sweep", flag.ExitOnError)
+	base := Params{}
+	registerParamFlags(fs, &base)
+	config := registerConfigFlags(fs)
+
+	var axes []sweepAxis
+	fs.Func("vary", "Parameter to vary as name=list or name=from:to[:step] (repeatable)", func(s string) error {
+		axis, err := parseSweepAxis(s)
+		axes = append(axes, axis)
+		return err
+	})
+	seeds := fs.Int("seeds", 3, "Runs (different seeds) per parameter combination")
+	workers := fs.Int("workers", runtime.NumCPU(), "Simulations to run at the same time")
+	out := fs.String("out", "sweep.csv", "CSV file for the per-run summaries")
+	fs.Parse(args)
+	config.apply(fs, &base)
+	checkParams(base)
+
+	if len(axes) == 0 {
+		fmt.Println("Error: sweep needs at least one -vary")
+		os.Exit(1)
+	}
+	if *seeds < 1 || *workers < 1 {
+		fmt.Println("Error: -seeds and -workers must be >= 1")
+		os.Exit(1)
+	}
+
+	// Values given on the command line or in the -config file apply to
+	// every run; the swept parameters are layered on top of them.
+	paramFlags := flag.NewFlagSet("params", flag.ContinueOnError)
+	registerParamFlags(paramFlags, &Params{})
+	fixed := map[string]string{}
+	fs.Visit(func(f *flag.Flag) {
+		if paramFlags.Lookup(f.Name) != nil {
+			fixed[f.Name] = f.Value.String()
+		}
+	})
+	firstSeed := base.Seed
+	if firstSeed == 0 {
+		firstSeed = 1
+	}
+
+	runs, err := sweepRuns(axes, fixed, *seeds, firstSeed)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Sweep: %d combinations x %d seeds = %d runs on %d workers\n",
+		len(runs) / *seeds, *seeds, len(runs), *workers)
+
+	results := make([]SweepSummary, len(runs))
+	errs := make([]error, len(runs))
+	runPool(len(runs), *workers, func(j int) {
+		results[j], errs[j] = summariseRun(runs[j].params)
+	})
+	if err := errors.Join(errs...); err != nil {
+		fmt.Println("Error creating world:", err)
+		os.Exit(1)
+	}
+
+	if err := writeSweepCSV(*out, axes, runs, results); err != nil {
+		fmt.Println("Error writing CSV file:", err)
+		os.Exit(1)
+	}
+	fmt.Println("Wrote", *out)
+}
+
+// runPool calls run(0) .. run(n-1) on a pool of workers goroutines and
+// waits for all of them, printing a progress counter as runs finish.
+func runPool(n, workers int, run func(i int)) {
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	done := 0
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				run(i)
+				mu.Lock()
+				done++
+				fmt.Printf("\r%d / %d runs", done, n)
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	fmt.Println()
+}
+
+// sweepRuns expands the axes into the full grid of parameter combinations,
+// each repeated for seeds consecutive seeds starting at firstSeed.
+func sweepRuns(axes []sweepAxis, fixed map[string]string, seeds int, firstSeed int64) ([]sweepRun, error) {
+	var runs []sweepRun
+	combo := make([]int, len(axes))
+	for {
+		values := map[string]string{}
+		for k, v := range fixed {
+			values[k] = v
+		}
+		for i, axis := range axes {
+			values[axis.name] = axis.values[combo[i]]
+		}
+		p, err := paramsWith(values)
+		if err != nil {
+			return nil, err
+		}
+		checkParams(p)
+		p = p.headless()
+
+		for s := 0; s < seeds; s++ {
+			p.Seed = firstSeed + int64(s)
+			runs = append(runs, sweepRun{combo: append([]int(nil), combo...), params: p})
+		}
+
+		// Advance the combination like an odometer.
+		i := len(axes) - 1
+		for ; i >= 0; i-- {
+			combo[i]++
+			if combo[i] < len(axes[i].values) {
+				break
+			}
+			combo[i] = 0
+		}
+		if i < 0 {
+			return runs, nil
+		}
+	}
+}
+
+// writeSweepCSV writes one row per run: the swept parameter values, the
+// seed and the run summary.
+func writeSweepCSV(path string, axes []sweepAxis, runs []sweepRun, results []SweepSummary) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	for _, axis := range axes {
+		fmt.Fprintf(w, "%s,", axis.name)
+	}
+	fmt.Fprintln(w, "seed,extinct,extinction_step,mean_fish,mean_sharks,period,steps,stop_reason")
+
+	for i, run := range runs {
+		for a, axis := range axes {
+			fmt.Fprintf(w, "%s,", axis.values[run.combo[a]])
+		}
+		r := results[i]
+		fmt.Fprintf(w, "%d,%s,%d,%.2f,%.2f,%d,%d,%s\n",
+			run.params.Seed, r.Extinct, r.ExtinctionStep, r.MeanFish, r.MeanSharks, r.Period,
+			r.Steps, r.StopReason)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,330 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// historyLen is the number of past population values shown in the
+// sparklines of the interactive view.
+const historyLen = 100
+
+// tuiDelays are the pauses between steps selectable with + and -, from
+// fastest to slowest. The run starts at the 50ms the plain text mode uses.
+var tuiDelays = []time.Duration{
+	0, 10 * time.Millisecond, 25 * time.Millisecond, 50 * time.Millisecond,
+	100 * time.Millisecond, 250 * time.Millisecond, 500 * time.Millisecond, time.Second,
+}
+
+// sparkRunes are the bar heights of a sparkline, lowest first.
+var sparkRunes = []rune("▁▂▃▄▅▆▇█")
+
+// defaultViewSize is the viewport side used when zooming in from the
+// overview without -viewSize.
+const defaultViewSize = 40
+
+// Keys other than plain characters, as sent by readKeys.
+const (
+	keyUp rune = -1 - iota
+	keyDown
+	keyRight
+	keyLeft
+)
+
+// tuiState is what the interactive view shows besides the grid.
+type tuiState struct {
+	step, fish, sharks int
+	dFish, dSharks     int     // population change over the last step
+	stepsPerSec        float64 // smoothed simulation speed
+	paused             bool
+	delay              int   // index into tuiDelays
+	view               View  // part of the ocean shown
+	zoom               int   // viewport side restored by z (the view is an overview)
+	fishHist           []int // last historyLen fish counts
+	sharkHist          []int // last historyLen shark counts
+}
+
+// RunInteractive runs the simulation in an interactive terminal view. The
+// terminal is switched to unbuffered input so single keys control the run:
+// space pauses and resumes, n advances one step while paused, + and -
+// change the speed, z switches between the overview and a viewport that
+// the arrow keys scroll, and q quits. A status bar shows the step, populations,
+// their rates of change and the speed, above sparklines of the last
+// historyLen fish and shark counts. Statistics are written to p.CSVFile as
+// in text mode.
+func RunInteractive(p Params) {
+	// The world is built before the terminal is changed, so an error
+	// leaves it as it was.
+	world, err := newOcean(p)
+	if err != nil {
+		fmt.Println("Error creating world:", err)
+		os.Exit(1)
+	}
+
+	restore, err := rawTerminal()
+	if err != nil {
+		fmt.Println("Error: interactive mode needs a terminal:", err)
+		os.Exit(1)
+	}
+	defer restore()
+
+	var stats *StatsWriter
+	if p.CSVFile != "" {
+		stats, err = NewStatsWriter(p.CSVFile, p.StatsFormat, newMetadata(p))
+		if err != nil {
+			restore()
+			fmt.Println("Error creating CSV file:", err)
+			os.Exit(1)
+		}
+	}
+
+	keys := make(chan rune)
+	go readKeys(keys)
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	stop, _ := newStopChecker(p) // already validated by checkParams
+	cells := world.Dim() * world.Dim()
+	st := tuiState{delay: 3, view: View{X: p.ViewX, Y: p.ViewY, Size: p.ViewSize}}
+	if p.ViewSize == 0 {
+		st.zoom = defaultViewSize
+	}
+	st.fish, st.sharks = world.Count()
+	res := RunResult{Steps: p.Steps}
+	start := time.Now()
+	last := start
+
+	renderer := NewRenderer(p, 1)
+	fmt.Print("\033[?25l\033[2J") // hide the cursor, clear once
+
+loop:
+	for st.step < p.Steps {
+		st.fishHist = appendHistory(st.fishHist, st.fish)
+		st.sharkHist = appendHistory(st.sharkHist, st.sharks)
+		if stats != nil {
+			stats.Row(st.step, st.fish, st.sharks)
+		}
+		if stop != nil {
+			if reason := stop.check(st.fish, st.sharks, cells); reason != "" {
+				res.Steps, res.StopReason = st.step, reason
+				break
+			}
+		}
+		drawTUI(renderer, world, p, st)
+
+		// Wait for the next step, handling keys in the meantime.
+		advance := false
+		timer := time.NewTimer(tuiDelays[st.delay])
+		for !advance {
+			var tick <-chan time.Time
+			if !st.paused {
+				tick = timer.C
+			}
+			select {
+			case <-tick:
+				advance = true
+			case <-interrupt:
+				res.Steps, res.StopReason = st.step, StopQuit
+				break loop
+			case k := <-keys:
+				switch k {
+				case ' ':
+					st.paused = !st.paused
+				case 'n', 'N':
+					advance = st.paused
+				case '+', '=':
+					st.delay = max(st.delay-1, 0)
+				case '-', '_':
+					st.delay = min(st.delay+1, len(tuiDelays)-1)
+				case 'z', 'Z':
+					st.view.Size, st.zoom = st.zoom, st.view.Size
+					fmt.Print("\033[2J") // the grid changes size
+					renderer.Invalidate()
+				case keyUp, keyDown, keyLeft, keyRight:
+					scroll(&st.view, k, world.Dim())
+				case 'q', 'Q':
+					res.Steps, res.StopReason = st.step, StopQuit
+					break loop
+				}
+				if !advance {
+					drawTUI(renderer, world, p, st)
+				}
+				if !st.paused && !advance {
+					timer.Reset(tuiDelays[st.delay])
+				}
+			}
+		}
+		timer.Stop()
+
+		if p.Threads > 1 {
+			world.StepParallel(p.Threads)
+		} else {
+			world.Step()
+		}
+		fish, sharks := world.Count()
+		st.dFish, st.dSharks = fish-st.fish, sharks-st.sharks
+		st.fish, st.sharks = fish, sharks
+		st.step++
+
+		now := time.Now()
+		if dt := now.Sub(last).Seconds(); dt > 0 {
+			st.stepsPerSec = 0.8*st.stepsPerSec + 0.2/dt
+		}
+		last = now
+	}
+	drawTUI(renderer, world, p, st)
+
+	res.Elapsed = time.Since(start)
+	res.Fish, res.Sharks = st.fish, st.sharks
+	restore()
+	fmt.Print("\033[?25h")
+	if res.StopReason != "" {
+		fmt.Printf("\nStopped early at step %d: %s\n", res.Steps, res.StopReason)
+	}
+	if stats != nil {
+		if err := stats.Close(res); err != nil {
+			fmt.Println("Error writing CSV file:", err)
+			os.Exit(1)
+		}
+	}
+	fmt.Printf("\nSimulation finished in %v\n", res.Elapsed)
+}
+
+// drawTUI redraws the interactive view: the changed cells of the grid,
+// then below it the status bar, the sparklines and a key reminder.
+func drawTUI(r *Renderer, o Ocean, p Params, st tuiState) {
+	r.Draw(o, st.view)
+	fmt.Printf("\033[%d;1H", 1+r.Rows())
+
+	state := fmt.Sprintf("delay %v", tuiDelays[st.delay])
+	if st.paused {
+		state = "PAUSED"
+	}
+	view := fmt.Sprintf("overview (%s)", p.BlockMode)
+	if st.view.Size > 0 {
+		n := min(st.view.Size, o.Dim())
+		view = fmt.Sprintf("view %dx%d at (%d,%d)", n, n, st.view.X, st.view.Y)
+	}
+	fmt.Printf("\nStep %d/%d  Fish %d (%+d)  Sharks %d (%+d)  %.1f steps/s  %s  %s\033[K\n",
+		st.step, p.Steps, st.fish, st.dFish, st.sharks, st.dSharks, st.stepsPerSec, state, view)
+	fmt.Printf("Fish   %s\033[K\n", sparkline(st.fishHist))
+	fmt.Printf("Sharks %s\033[K\n", sparkline(st.sharkHist))
+	fmt.Print("[space] pause  [n] step  [+/-] speed  [z] zoom  [arrows] scroll  [q] quit\033[K\033[J")
+}
+
+// scroll moves a viewport by an eighth of its side in the direction of an
+// arrow key. The overview cannot be scrolled.
+func scroll(v *View, key rune, n int) {
+	if v.Size == 0 {
+		return
+	}
+	d := max(1, min(v.Size, n)/8)
+	switch key {
+	case keyUp:
+		v.Scroll(0, -d, n)
+	case keyDown:
+		v.Scroll(0, d, n)
+	case keyLeft:
+		v.Scroll(-d, 0, n)
+	case keyRight:
+		v.Scroll(d, 0, n)
+	}
+}
+
+// appendHistory adds v to a history of at most historyLen values,
+// dropping the oldest.
+func appendHistory(h []int, v int) []int {
+	if len(h) == historyLen {
+		copy(h, h[1:])
+		h = h[:historyLen-1]
+	}
+	return append(h, v)
+}
+
+// sparkline draws values as a row of bars scaled between their minimum
+// and maximum.
+func sparkline(values []int) string {
+	if len(values) == 0 {
+		return ""
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo, hi = min(lo, v), max(hi, v)
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		i := 0
+		if hi > lo {
+			i = (v - lo) * (len(sparkRunes) - 1) / (hi - lo)
+		}
+		b.WriteRune(sparkRunes[i])
+	}
+	return b.String()
+}
+
+// readKeys sends the keys typed on standard input to keys: the arrow keys
+// (ANSI sequences ESC [ A..D) as keyUp..keyLeft and other bytes as they are.
+func readKeys(keys chan<- rune) {
+	arrows := map[byte]rune{'A': keyUp, 'B': keyDown, 'C': keyRight, 'D': keyLeft}
+	buf := make([]byte, 64)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return
+		}
+		for i := 0; i < n; i++ {
+			if buf[i] == 0x1b && i+2 < n && buf[i+1] == '[' {
+				if k, ok := arrows[buf[i+2]]; ok {
+					keys <- k
+					i += 2
+					continue
+				}
+			}
+			keys <- rune(buf[i])
+		}
+	}
+}
+
+// rawTerminal switches the terminal to unbuffered, unechoed input with the
+// stty command and returns a function that restores the previous mode.
+// It is not supported on Windows, which has no stty.
+func rawTerminal() (restore func(), err error) {
+	if runtime.GOOS == "windows" {
+		return nil, fmt.Errorf("not supported on Windows (use -graphics)")
+	}
+
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stty("-icanon", "-echo", "min", "1"); err != nil {
+		return nil, err
+	}
+	done := false
+	return func() {
+		if !done {
+			done = true
+			stty(strings.TrimSpace(saved))
+		}
+	}, nil
+}
+
+// stty runs the stty command on the terminal attached to standard input.
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return string(out), err
+}
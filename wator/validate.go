@@ -0,0 +1,145 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Largest grid sides accepted. A World keeps a pointer per cell, so
+// bigger worlds have to use the chunked storage (2 bytes per cell, twice).
+const (
+	maxWorldSize   = 1 << 14
+	maxChunkedSize = 1 << 16
+)
+
+// checker collects the problems found by Validate and ValidateRun.
+type checker []error
+
+// check records the error described by format and args unless ok.
+func (c *checker) check(ok bool, format string, args ...any) {
+	if !ok {
+		*c = append(*c, fmt.Errorf(format, args...))
+	}
+}
+
+// Validate checks the fields of p that NewWorld and NewChunkedWorld use:
+// the grid, the populations and the rules. It reports all problems at
+// once, joined with errors.Join, each naming the command-line flag at
+// fault. It returns nil if a world can be built from p; the options of a
+// run from the command line are checked by ValidateRun.
+func (p Params) Validate() error {
+	var c checker
+	p.validateWorld(&c)
+	return errors.Join(c...)
+}
+
+// validateWorld adds the problems Validate reports to c.
+func (p Params) validateWorld(c *checker) {
+	check := c.check
+
+	// The world and its population.
+	check(p.GridSize >= 1, "gridSize must be >= 1 (got %d)", p.GridSize)
+	if p.Chunked {
+		check(p.GridSize <= maxChunkedSize, "gridSize must be <= %d (got %d)", maxChunkedSize, p.GridSize)
+	} else {
+		check(p.GridSize <= maxWorldSize, "gridSize must be <= %d without -chunked (got %d)", maxWorldSize, p.GridSize)
+	}
+	check(p.NumFish >= 0, "numFish must be >= 0 (got %d)", p.NumFish)
+	check(p.NumShark >= 0, "numShark must be >= 0 (got %d)", p.NumShark)
+	if p.GridSize >= 1 && p.GridSize <= maxChunkedSize && p.NumFish >= 0 && p.NumShark >= 0 {
+		cells := p.GridSize * p.GridSize
+		check(p.NumFish+p.NumShark <= cells, "numFish + numShark (%d) must fit in the %d cells of a %dx%d grid",
+			p.NumFish+p.NumShark, cells, p.GridSize, p.GridSize)
+	}
+
+	// The rules.
+	check(p.FishBreed >= 1, "fishBreed must be >= 1 (got %d)", p.FishBreed)
+	check(p.SharkBreed >= 1, "sharkBreed must be >= 1 (got %d)", p.SharkBreed)
+	check(p.Starve >= 1, "starve must be >= 1 (got %d)", p.Starve)
+	if p.Chunked {
+		// Counters saturate at maxCellCounter in a chunked Cell.
+		check(p.FishBreed <= maxCellCounter, "fishBreed must be <= %d with -chunked (got %d)", maxCellCounter, p.FishBreed)
+		check(p.SharkBreed <= maxCellCounter, "sharkBreed must be <= %d with -chunked (got %d)", maxCellCounter, p.SharkBreed)
+		check(p.Starve <= maxCellCounter, "starve must be <= %d with -chunked (got %d)", maxCellCounter, p.Starve)
+	}
+}
+
+// ValidateRun checks everything Validate does and the options of a run
+// from the command line: its length and threads, the text and graphics
+// output, the statistics files, the stop conditions and -compare. The
+// problems are reported like Validate.
+func (p Params) ValidateRun() error {
+	var errs checker
+	p.validateWorld(&errs)
+	check := errs.check
+
+	// The run.
+	check(p.Steps >= 1, "steps must be >= 1 (got %d)", p.Steps)
+	check(p.Threads >= 1, "threads must be >= 1 (got %d)", p.Threads)
+	if p.GridSize >= 1 {
+		check(p.Threads <= p.GridSize, "threads (%d) must be <= the %d rows of the grid", p.Threads, p.GridSize)
+	}
+	check(p.PrintEvery >= 0, "printEvery must be >= 0 (got %d)", p.PrintEvery)
+	check(p.DisplaySize >= 0, "displaySize must be >= 0 (got %d)", p.DisplaySize)
+	check(p.PixelSize >= 1, "pixelSize must be >= 1 (got %d)", p.PixelSize)
+	check(p.WindowScale > 0, "windowScale must be > 0 (got %g)", p.WindowScale)
+	check(p.StepsPerSec >= 0, "sps must be >= 0 (got %g)", p.StepsPerSec)
+	check(p.RenderMode == RenderASCII || p.RenderMode == RenderHalfBlock,
+		"render must be %s or %s (got %q)", RenderASCII, RenderHalfBlock, p.RenderMode)
+	check(p.BlockMode == BlockMajority || p.BlockMode == BlockDensity,
+		"blockMode must be %s or %s (got %q)", BlockMajority, BlockDensity, p.BlockMode)
+	check(p.ViewSize >= 0, "viewSize must be >= 0 (got %d)", p.ViewSize)
+	check(p.ViewX >= 0 && p.ViewY >= 0, "viewX and viewY must be >= 0 (got %d, %d)", p.ViewX, p.ViewY)
+	check(p.Replicates >= 1, "replicates must be >= 1 (got %d)", p.Replicates)
+	check(!(p.Graphics && p.Interactive), "graphics and tui cannot be used together")
+	check(p.Replicates <= 1 || !(p.Graphics || p.Interactive),
+		"replicates runs an ensemble without a display; it cannot be used with -graphics or -tui")
+
+	// Output.
+	check(p.StatsFormat == FormatCSV || p.StatsFormat == FormatJSONL,
+		"statsFormat must be %s or %s (got %q)", FormatCSV, FormatJSONL, p.StatsFormat)
+	check(p.RecordFormat == RecordGIF || p.RecordFormat == RecordPNG,
+		"recordFormat must be %s or %s (got %q)", RecordGIF, RecordPNG, p.RecordFormat)
+	check(p.SpatialEvery >= 1, "spatialEvery must be >= 1 (got %d)", p.SpatialEvery)
+	check(p.SpatialRange >= 1, "spatialRange must be >= 1 (got %d)", p.SpatialRange)
+	check(p.SpatialCSV == "" || !(p.Graphics || p.Interactive || p.Replicates > 1),
+		"spatialCSV is only written in text mode, not with -graphics, -tui or -replicates")
+	if p.SpatialCSV != "" && p.SpatialRange >= 1 && p.GridSize >= 1 {
+		// From half the grid on, distances wrap around the torus and
+		// the same pair of cells would be counted twice.
+		check(2*p.SpatialRange < p.GridSize, "spatialRange (%d) must be less than half the grid size (%d)",
+			p.SpatialRange, p.GridSize)
+	}
+
+	if _, err := newStopChecker(p); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Comparison: each world must be valid on its own. Its problems are
+	// only reported once the shared parameters are fine, so they are not
+	// repeated for every world.
+	if p.Compare != "" {
+		check(p.Graphics, "compare requires -graphics")
+		sets, err := parseCompare(p, p.Compare)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("compare: %w", err))
+		}
+		for _, s := range sets {
+			if len(errs) > 0 {
+				break
+			}
+			if err := s.params.ValidateRun(); err != nil {
+				for _, line := range strings.Split(err.Error(), "\n") {
+					errs = append(errs, fmt.Errorf("compare %s: %s", s.label, line))
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,132 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// checkErrors reports whether err holds exactly the problems in want, each
+// given by part of its message. want = nil means err should be nil.
+func checkErrors(t *testing.T, name string, err error, want []string) {
+	t.Helper()
+	if err == nil {
+		if want != nil {
+			t.Errorf("%s: no error, want %q", name, want)
+		}
+		return
+	}
+	lines := strings.Split(err.Error(), "\n")
+	if len(lines) != len(want) {
+		t.Errorf("%s: got %d problems %q, want %q", name, len(lines), lines, want)
+		return
+	}
+	for i, line := range lines {
+		if !strings.Contains(line, want[i]) {
+			t.Errorf("%s: problem %d is %q, want %q", name, i, line, want[i])
+		}
+	}
+}
+
+// TestValidate checks the world parameters on Params built by a library
+// caller, with none of the command-line options set.
+func TestValidate(t *testing.T) {
+	library := Params{GridSize: 20, NumFish: 10, NumShark: 5, FishBreed: 3, SharkBreed: 5, Starve: 3}
+
+	tests := []struct {
+		name   string
+		change func(*Params)
+		want   []string
+	}{
+		{"library params", func(p *Params) {}, nil},
+		{"full grid", func(p *Params) { p.NumFish, p.NumShark = 300, 100 }, nil},
+		{"overfull grid", func(p *Params) { p.NumFish = 396 }, []string{"numFish + numShark (401) must fit in the 400 cells"}},
+		{"empty grid", func(p *Params) { p.GridSize = 0 }, []string{"gridSize must be >= 1 (got 0)"}},
+		{"negative populations", func(p *Params) { p.NumFish, p.NumShark = -1, -2 },
+			[]string{"numFish must be >= 0", "numShark must be >= 0"}},
+		{"every rule", func(p *Params) { p.FishBreed, p.SharkBreed, p.Starve = 0, 0, -1 },
+			[]string{"fishBreed must be >= 1", "sharkBreed must be >= 1", "starve must be >= 1"}},
+		{"largest world", func(p *Params) { p.GridSize = maxWorldSize }, nil},
+		{"world too big", func(p *Params) { p.GridSize = maxWorldSize + 1 }, []string{"without -chunked"}},
+		{"big chunked world", func(p *Params) { p.GridSize, p.Chunked = maxWorldSize+1, true }, nil},
+		{"chunked world too big", func(p *Params) { p.GridSize, p.Chunked = maxChunkedSize+1, true },
+			[]string{"gridSize must be <= 65536 (got 65537)"}},
+		{"chunked counters", func(p *Params) {
+			p.Chunked, p.FishBreed, p.SharkBreed, p.Starve = true, maxCellCounter, maxCellCounter, maxCellCounter
+		}, nil},
+		{"chunked counters overflow", func(p *Params) {
+			p.Chunked, p.FishBreed, p.Starve = true, maxCellCounter+1, maxCellCounter+1
+		}, []string{"fishBreed must be <=", "starve must be <="}},
+		{"counters without -chunked", func(p *Params) { p.Starve = 1000 }, nil},
+		{"run options are not checked", func(p *Params) { p.Steps, p.Threads, p.StopOn = 0, 0, "never" }, nil},
+	}
+	for _, tt := range tests {
+		p := library
+		tt.change(&p)
+		checkErrors(t, tt.name, p.Validate(), tt.want)
+	}
+}
+
+// TestValidateRun checks the options of a run on top of the defaults of
+// the command line.
+func TestValidateRun(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(*Params)
+		want   []string
+	}{
+		{"defaults", func(p *Params) {}, nil},
+		{"library params", func(p *Params) {
+			*p = Params{GridSize: 20, NumFish: 10, NumShark: 5, FishBreed: 3, SharkBreed: 5, Starve: 3}
+		}, []string{"steps must be >= 1", "threads must be >= 1", "pixelSize must be >= 1", "windowScale must be > 0",
+			"render must be", "blockMode must be", "replicates must be >= 1", "statsFormat must be",
+			"recordFormat must be", "spatialEvery must be >= 1", "spatialRange must be >= 1"}},
+		{"world and run together", func(p *Params) { p.Starve, p.Steps = 0, 0 },
+			[]string{"starve must be >= 1", "steps must be >= 1"}},
+		{"one thread per row", func(p *Params) { p.GridSize, p.NumFish, p.NumShark, p.Threads = 8, 10, 5, 8 }, nil},
+		{"more threads than rows", func(p *Params) { p.GridSize, p.NumFish, p.NumShark, p.Threads = 8, 10, 5, 9 },
+			[]string{"threads (9) must be <= the 8 rows of the grid"}},
+		{"graphics and tui", func(p *Params) { p.Graphics, p.Interactive = true, true },
+			[]string{"graphics and tui cannot be used together"}},
+		{"ensemble", func(p *Params) { p.Replicates = 5 }, nil},
+		{"ensemble in graphics", func(p *Params) { p.Replicates, p.Graphics = 5, true },
+			[]string{"replicates runs an ensemble without a display"}},
+		{"ensemble in the terminal", func(p *Params) { p.Replicates, p.Interactive = 5, true },
+			[]string{"replicates runs an ensemble without a display"}},
+		{"spatial statistics in text mode", func(p *Params) { p.SpatialCSV = "s.csv" }, nil},
+		{"spatial statistics in graphics", func(p *Params) { p.SpatialCSV, p.Graphics = "s.csv", true },
+			[]string{"spatialCSV is only written in text mode"}},
+		{"spatial statistics of an ensemble", func(p *Params) { p.SpatialCSV, p.Replicates = "s.csv", 5 },
+			[]string{"spatialCSV is only written in text mode"}},
+		{"record format", func(p *Params) { p.RecordFormat = "mp4" }, []string{`recordFormat must be gif or png (got "mp4")`}},
+		{"stats format", func(p *Params) { p.StatsFormat = "xml" }, []string{`statsFormat must be csv or jsonl (got "xml")`}},
+		{"spatial range below half", func(p *Params) { p.SpatialCSV, p.SpatialRange = "s.csv", p.GridSize/2-1 }, nil},
+		{"spatial range at half", func(p *Params) { p.SpatialCSV, p.SpatialRange = "s.csv", p.GridSize/2 },
+			[]string{"spatialRange (10) must be less than half the grid size (20)"}},
+		{"spatial range without -spatialCSV", func(p *Params) { p.SpatialRange = p.GridSize / 2 }, nil},
+		{"stop conditions", func(p *Params) { p.StopOn = "extinct, saturated" }, nil},
+		{"unknown stop condition", func(p *Params) { p.StopOn = "extinct,bored" }, []string{`unknown stop condition "bored"`}},
+		{"stationary window", func(p *Params) { p.StopOn, p.StationaryWindow = "stationary", 1 },
+			[]string{"stationary stop needs stationaryWindow >= 2"}},
+		{"comparison", func(p *Params) { p.Graphics, p.Compare = true, "starve=2;starve=4" }, nil},
+		{"comparison without graphics", func(p *Params) { p.Compare = "starve=2;starve=4" },
+			[]string{"compare requires -graphics"}},
+		{"comparison of one world", func(p *Params) { p.Graphics, p.Compare = true, "starve=2" },
+			[]string{"compare: need 2 to 9 parameter sets"}},
+		{"comparison of a fixed parameter", func(p *Params) { p.Graphics, p.Compare = true, "gridSize=10;gridSize=20" },
+			[]string{"compare: gridSize cannot differ between the worlds"}},
+		{"invalid world in a comparison", func(p *Params) { p.Graphics, p.Compare = true, "starve=0;starve=3" },
+			[]string{"compare starve=0: starve must be >= 1 (got 0)"}},
+		{"comparison after other problems", func(p *Params) { p.Steps, p.Graphics, p.Compare = 0, true, "starve=0;starve=3" },
+			[]string{"steps must be >= 1"}},
+	}
+	for _, tt := range tests {
+		p := defaultParams()
+		tt.change(&p)
+		checkErrors(t, tt.name, p.ValidateRun(), tt.want)
+	}
+}
@@ -0,0 +1,116 @@
+//--------------------------------
+//Author: Abdulaziz Hameed Aloufi
+//Student ID: C00266252
+//--------------------------------
+
+package main
+
+// How the overview summarises a block of cells, selected with -blockMode.
+const (
+	BlockMajority = "majority" // the kind occupying most of the block
+	BlockDensity  = "density"  // how full the block is, coloured by the commoner species
+)
+
+// densityRamp are the ASCII characters for increasingly full blocks.
+const densityRamp = ".:-=+*#%@"
+
+// View selects the part of the ocean shown in text mode: either a
+// viewport of cells at full resolution, or an overview of the whole ocean.
+type View struct {
+	X, Y int // top-left cell of the viewport; it wraps around the torus
+	Size int // cells shown along each side (0 = overview of the whole ocean)
+}
+
+// Scroll moves the viewport by dx, dy cells on a torus of side n.
+func (v *View) Scroll(dx, dy, n int) {
+	v.X = ((v.X+dx)%n + n) % n
+	v.Y = ((v.Y+dy)%n + n) % n
+}
+
+// block is the make-up of the cells behind one displayed character.
+type block struct {
+	fish, sharks, cells int
+}
+
+// viewBlocks returns the blocks shown by v, row by row, and the number of
+// blocks along each side. A viewport shows one cell per block; the
+// overview divides the ocean into at most displaySize blocks per side
+// (0 = one cell per block).
+func viewBlocks(o Ocean, v View, displaySize int) ([]block, int) {
+	size := o.Dim()
+	if v.Size > 0 {
+		n := min(v.Size, size)
+		blocks := make([]block, 0, n*n)
+		for j := 0; j < n; j++ {
+			y := (v.Y + j) % size
+			for i := 0; i < n; i++ {
+				var b block
+				switch o.CellAt((v.X+i)%size, y) {
+				case FishCell:
+					b.fish = 1
+				case SharkCell:
+					b.sharks = 1
+				}
+				b.cells = 1
+				blocks = append(blocks, b)
+			}
+		}
+		return blocks, n
+	}
+
+	n := size
+	if displaySize > 0 && n > displaySize {
+		n = displaySize
+	}
+	blocks := make([]block, 0, n*n)
+	for j := 0; j < n; j++ {
+		y0, y1 := blockBounds(j, n, size)
+		for i := 0; i < n; i++ {
+			x0, x1 := blockBounds(i, n, size)
+			fish, sharks, cells := blockCounts(o, x0, y0, x1, y1)
+			blocks = append(blocks, block{fish, sharks, cells})
+		}
+	}
+	return blocks, n
+}
+
+// asciiGlyph returns the character and ANSI colour for a block: the
+// majority kind as "f", "S" or ".", or in density mode a character from
+// densityRamp for how full the block is.
+func asciiGlyph(b block, mode string) glyph {
+	if mode == BlockDensity && b.fish+b.sharks > 0 {
+		level := (b.fish + b.sharks) * (len(densityRamp) - 1) / b.cells
+		colour := int32(32)
+		if b.sharks > b.fish {
+			colour = 31
+		}
+		return glyph{rune(densityRamp[level]), colour, -1}
+	}
+	switch majority(b.fish, b.sharks, b.cells) {
+	case FishCell:
+		return glyph{'f', 32, -1}
+	case SharkCell:
+		return glyph{'S', 31, -1}
+	default:
+		return glyph{'.', 34, -1}
+	}
+}
+
+// blockRGB returns the 0xRRGGBB colour of a block: the colours of its
+// cells blended in density mode, or the colour of the majority kind.
+func blockRGB(b block, mode string) int32 {
+	var c [3]byte
+	if mode == BlockDensity {
+		c = blend(b.fish, b.sharks, b.cells)
+	} else {
+		switch majority(b.fish, b.sharks, b.cells) {
+		case FishCell:
+			c = blend(1, 0, 1)
+		case SharkCell:
+			c = blend(0, 1, 1)
+		default:
+			c = blend(0, 0, 1)
+		}
+	}
+	return int32(c[0])<<16 | int32(c[1])<<8 | int32(c[2])
+}
@@ -6,14 +6,14 @@
 package main
 
 import (
-	"fmt"
 	"math/rand"
-	"os"
+	"slices"
 	"sync"
 	"time"
 )
 
-// CellType represents the contents of a grid cell: empty, fish or shark.
+// CellType represents the contents of a grid cell: empty, fish, shark or
+// rock.
 type CellType int
 
 const (
@@ -23,6 +23,8 @@ const (
 	FishCell
 	// SharkCell means the cell is occupied by a shark.
 	SharkCell
+	// RockCell means the cell holds an obstacle painted in graphics mode.
+	RockCell
 )
 
 // Creature represents either a fish or a shark living in the grid.
@@ -31,6 +33,36 @@ type Creature struct {
 	Kind         CellType // FishCell or SharkCell
 	BreedCounter int      // chronons since last reproduction
 	Energy       int      // used only for sharks; 0 for fish
+	Age          int      // chronons since birth
+}
+
+// Ocean is the common interface implemented by World and ChunkedWorld. The
+// simulation loop and the renderers only use these methods, so either
+// storage layout can be driven and displayed the same way.
+type Ocean interface {
+	Dim() int                  // width and height of the toroidal grid
+	CellAt(x, y int) CellType  // contents of the cell at (x, y)
+	Count() (fish, sharks int) // current population totals
+	Step()                     // advance one chronon sequentially
+	StepParallel(threads int)  // advance one chronon using several goroutines
+	PhaseTimes() PhaseTimes    // time spent so far in the fish, shark and merge phases
+
+	SetCell(x, y int, kind CellType) // place a new creature or rock at (x, y), or empty it
+
+	Info(x, y int) CellInfo // attributes of the creature at (x, y)
+	TrackPredation()        // start counting the fish eaten in each cell
+	Predation(x, y int) int // fish eaten at (x, y) since TrackPredation
+
+	SetParams(p Params) // use the breeding and starvation times of p from the next step on
+}
+
+// newOcean creates the world representation selected by p.Chunked. It
+// returns the errors of p.Validate if the parameters are unusable.
+func newOcean(p Params) (Ocean, error) {
+	if p.Chunked {
+		return NewChunkedWorld(p)
+	}
+	return NewWorld(p)
 }
 
 // World holds the simulation grid and the parameters used to evolve it.
@@ -38,6 +70,23 @@ type World struct {
 	Size   int
 	Grid   [][]*Creature
 	Params Params
+
+	phases PhaseTimes // accumulated Fish, Shark and Merge times
+	rng    *rand.Rand // random source for the sequential step
+	rocks  rockMask   // obstacles; rock cells have a nil Grid entry
+
+	predation predationMap // fish eaten per cell, once TrackPredation is called
+}
+
+// Dim returns the width and height of the grid.
+func (w *World) Dim() int {
+	return w.Size
+}
+
+// PhaseTimes returns the time spent so far in the fish and shark phases and
+// in building and merging grids.
+func (w *World) PhaseTimes() PhaseTimes {
+	return w.phases
 }
 
 // CellAt returns the CellType at coordinates (x, y). If the grid cell is
@@ -45,20 +94,66 @@ type World struct {
 func (w *World) CellAt(x, y int) CellType {
 	c := w.Grid[y][x]
 	if c == nil {
+		if w.rocks.has(x, y) {
+			return RockCell
+		}
 		return Empty
 	}
 	return c.Kind
 }
 
+// Info returns the kind, breed counter, energy and age of the creature at
+// (x, y).
+func (w *World) Info(x, y int) CellInfo {
+	c := w.Grid[y][x]
+	if c == nil {
+		return CellInfo{Kind: w.CellAt(x, y)}
+	}
+	return CellInfo{Kind: c.Kind, Breed: c.BreedCounter, Energy: c.Energy, Age: c.Age}
+}
+
+// TrackPredation starts counting the fish eaten in each cell.
+func (w *World) TrackPredation() { w.predation.enable(w.Size) }
+
+// SetParams replaces the parameters of the world between two steps.
+// Creatures keep their counters; the new breeding and starvation times
+// apply from the next step on.
+func (w *World) SetParams(p Params) { w.Params = p }
+
+// Predation returns the number of fish eaten at (x, y) since
+// TrackPredation was called.
+func (w *World) Predation(x, y int) int { return w.predation.at(x, y) }
+
+// SetCell replaces the contents of cell (x, y) with a new fish, a new
+// shark with full energy, a rock or nothing (Empty). It must not be called
+// during a step.
+func (w *World) SetCell(x, y int, kind CellType) {
+	w.Grid[y][x] = nil
+	switch kind {
+	case FishCell:
+		w.Grid[y][x] = &Creature{Kind: FishCell}
+	case SharkCell:
+		w.Grid[y][x] = &Creature{Kind: SharkCell, Energy: w.Params.Starve}
+	}
+	w.rocks.set(x, y, w.Size, kind == RockCell)
+}
+
 // NewWorld creates a new toroidal Wa-Tor world with randomly placed
-// fish and sharks according to the given parameters.
-func NewWorld(p Params) *World {
-	rand.Seed(time.Now().UnixNano())
+// fish and sharks according to the given parameters. The same p.Seed
+// always gives the same run; a zero seed is replaced by a time-based one,
+// which is recorded in the world's Params. It returns the errors of
+// p.Validate if the parameters are unusable.
+func NewWorld(p Params) (*World, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	p.Seed = resolveSeed(p.Seed)
 
 	w := &World{
 		Size:   p.GridSize,
 		Grid:   make([][]*Creature, p.GridSize),
 		Params: p,
+		rng:    rand.New(rand.NewSource(p.Seed)),
 	}
 
 	for y := 0; y < p.GridSize; y++ {
@@ -66,13 +161,9 @@ func NewWorld(p Params) *World {
 	}
 
 	totalCells := p.GridSize * p.GridSize
-	if p.NumFish+p.NumShark > totalCells {
-		fmt.Println("Error: more creatures than cells in the grid")
-		os.Exit(1)
-	}
 
 	// Create a random permutation of all cell indices.
-	positions := rand.Perm(totalCells)
+	positions := w.rng.Perm(totalCells)
 	idx := 0
 
 	// Place fish.
@@ -103,42 +194,15 @@ func NewWorld(p Params) *World {
 		}
 	}
 
-	return w
+	return w, nil
 }
 
-// PrintColored prints an ANSI-coloured ASCII representation of the world.
-// Fish are rendered in green, sharks in red and empty cells as blue dots.
-func (w *World) PrintColored() {
-
-	const (
-		reset = "\033[0m"
-		blue  = "\033[34m"
-		green = "\033[32m"
-		red   = "\033[31m"
-	)
-
-	for y := 0; y < w.Size; y++ {
-		for x := 0; x < w.Size; x++ {
-			c := w.Grid[y][x]
-			if c == nil {
-				fmt.Printf("%s.%s ", blue, reset)
-				continue
-			}
-			switch c.Kind {
-			case FishCell:
-				fmt.Printf("%sf%s ", green, reset)
-			case SharkCell:
-				fmt.Printf("%sS%s ", red, reset)
-			}
-		}
-		fmt.Println()
+// resolveSeed returns seed, or a time-based seed if it is zero.
+func resolveSeed(seed int64) int64 {
+	if seed == 0 {
+		return time.Now().UnixNano()
 	}
-}
-
-// Print is a small convenience wrapper that prints the world using
-// coloured ASCII output.
-func (w *World) Print() {
-	w.PrintColored()
+	return seed
 }
 
 // Count returns the total number of fish and sharks currently in the world.
@@ -163,10 +227,13 @@ func (w *World) Count() (fish int, sharks int) {
 // updates all fish, then all sharks, writing into a new grid and
 // finally swaps the new grid into place.
 func (w *World) Step() {
+	start := time.Now()
 	newGrid := make([][]*Creature, w.Size)
 	for y := 0; y < w.Size; y++ {
 		newGrid[y] = make([]*Creature, w.Size)
 	}
+	fishStart := time.Now()
+	w.phases.Merge += fishStart.Sub(start)
 
 	// --- FISH PHASE ---
 	for y := 0; y < w.Size; y++ {
@@ -175,9 +242,11 @@ func (w *World) Step() {
 			if c == nil || c.Kind != FishCell {
 				continue
 			}
-			w.updateFish(x, y, c, newGrid)
+			w.updateFish(x, y, c, newGrid, w.rng)
 		}
 	}
+	sharkStart := time.Now()
+	w.phases.Fish += sharkStart.Sub(fishStart)
 
 	// --- SHARK PHASE ---
 	for y := 0; y < w.Size; y++ {
@@ -186,17 +255,20 @@ func (w *World) Step() {
 			if c == nil || c.Kind != SharkCell {
 				continue
 			}
-			w.updateShark(x, y, c, newGrid)
+			w.updateShark(x, y, c, newGrid, w.rng)
 		}
 	}
+	w.phases.Shark += time.Since(sharkStart)
 
 	w.Grid = newGrid
 }
 
 // StepParallel performs one chronon of the simulation using multiple
-// goroutines. Each worker writes into its own private grid, and the
-// grids are merged afterwards. When both a fish and a shark contend
-// for the same cell, the shark wins.
+// goroutines. Each worker writes into its own private grid, using its
+// own random source seeded from the world's, and the grids are merged
+// afterwards. When both a fish and a shark contend
+// for the same cell, the shark wins. The fish and shark phase times are
+// those of the slowest worker, since the workers run them concurrently.
 func (w *World) StepParallel(threads int) {
 	if threads <= 1 {
 		w.Step()
@@ -206,6 +278,8 @@ func (w *World) StepParallel(threads int) {
 		threads = w.Size
 	}
 
+	start := time.Now()
+
 	// Each worker gets its own private newGrid.
 	localGrids := make([][][]*Creature, threads)
 	for t := 0; t < threads; t++ {
@@ -218,6 +292,9 @@ func (w *World) StepParallel(threads int) {
 
 	rowsPerThread := (w.Size + threads - 1) / threads
 	var wg sync.WaitGroup
+	fishTimes := make([]time.Duration, threads)
+	sharkTimes := make([]time.Duration, threads)
+	w.phases.Merge += time.Since(start)
 
 	for t := 0; t < threads; t++ {
 		startY := t * rowsPerThread
@@ -230,10 +307,12 @@ func (w *World) StepParallel(threads int) {
 		}
 
 		localGrid := localGrids[t]
+		rng := rand.New(rand.NewSource(w.rng.Int63()))
 
 		wg.Add(1)
-		go func(startY, endY int, lg [][]*Creature) {
+		go func(t, startY, endY int, lg [][]*Creature, rng *rand.Rand) {
 			defer wg.Done()
+			fishStart := time.Now()
 
 			// FISH PHASE on assigned rows (reading from shared w.Grid).
 			for y := startY; y < endY; y++ {
@@ -242,9 +321,11 @@ func (w *World) StepParallel(threads int) {
 					if c == nil || c.Kind != FishCell {
 						continue
 					}
-					w.updateFish(x, y, c, lg)
+					w.updateFish(x, y, c, lg, rng)
 				}
 			}
+			sharkStart := time.Now()
+			fishTimes[t] = sharkStart.Sub(fishStart)
 
 			// SHARK PHASE on assigned rows.
 			for y := startY; y < endY; y++ {
@@ -253,13 +334,17 @@ func (w *World) StepParallel(threads int) {
 					if c == nil || c.Kind != SharkCell {
 						continue
 					}
-					w.updateShark(x, y, c, lg)
+					w.updateShark(x, y, c, lg, rng)
 				}
 			}
-		}(startY, endY, localGrid)
+			sharkTimes[t] = time.Since(sharkStart)
+		}(t, startY, endY, localGrid, rng)
 	}
 
 	wg.Wait()
+	w.phases.Fish += slices.Max(fishTimes)
+	w.phases.Shark += slices.Max(sharkTimes)
+	mergeStart := time.Now()
 
 	// Merge local grids into final newGrid.
 	newGrid := make([][]*Creature, w.Size)
@@ -286,6 +371,7 @@ func (w *World) StepParallel(threads int) {
 	}
 
 	w.Grid = newGrid
+	w.phases.Merge += time.Since(mergeStart)
 }
 
 // neighbours returns the 4-neighbour coordinates (N,E,S,W) around (x, y),
@@ -305,7 +391,7 @@ func (w *World) emptyNeighbours(x, y int) [][2]int {
 	result := make([][2]int, 0, 4)
 	for _, n := range w.neighbours(x, y) {
 		nx, ny := n[0], n[1]
-		if w.Grid[ny][nx] == nil {
+		if w.Grid[ny][nx] == nil && !w.rocks.has(nx, ny) {
 			result = append(result, [2]int{nx, ny})
 		}
 	}
@@ -328,8 +414,9 @@ func (w *World) fishNeighbours(x, y int) [][2]int {
 // updateFish applies the Wa-Tor rules for a single fish at (x, y).
 // It chooses a random empty neighbour to move into and handles breeding
 // by optionally leaving a new fish behind.
-func (w *World) updateFish(x, y int, c *Creature, newGrid [][]*Creature) {
+func (w *World) updateFish(x, y int, c *Creature, newGrid [][]*Creature, rng *rand.Rand) {
 	breed := c.BreedCounter + 1
+	age := c.Age + 1
 
 	// Find empty neighbouring cells (based on old grid).
 	empties := w.emptyNeighbours(x, y)
@@ -341,13 +428,14 @@ func (w *World) updateFish(x, y int, c *Creature, newGrid [][]*Creature) {
 				Kind:         FishCell,
 				BreedCounter: breed,
 				Energy:       0,
+				Age:          age,
 			}
 		}
 		return
 	}
 
 	// Choose a random destination.
-	dest := empties[rand.Intn(len(empties))]
+	dest := empties[rng.Intn(len(empties))]
 	dx, dy := dest[0], dest[1]
 
 	// If someone already took that spot in the new grid, the fish fails to move.
@@ -357,6 +445,7 @@ func (w *World) updateFish(x, y int, c *Creature, newGrid [][]*Creature) {
 				Kind:         FishCell,
 				BreedCounter: breed,
 				Energy:       0,
+				Age:          age,
 			}
 		}
 		return
@@ -376,6 +465,7 @@ func (w *World) updateFish(x, y int, c *Creature, newGrid [][]*Creature) {
 			Kind:         FishCell,
 			BreedCounter: 0,
 			Energy:       0,
+			Age:          age,
 		}
 	} else {
 		// Just move, no reproduction.
@@ -383,6 +473,7 @@ func (w *World) updateFish(x, y int, c *Creature, newGrid [][]*Creature) {
 			Kind:         FishCell,
 			BreedCounter: breed,
 			Energy:       0,
+			Age:          age,
 		}
 	}
 }
@@ -391,7 +482,7 @@ func (w *World) updateFish(x, y int, c *Creature, newGrid [][]*Creature) {
 // The shark first loses energy, then preferentially moves to an adjacent
 // fish cell (eating the fish and gaining energy) or otherwise to an empty
 // cell. It may reproduce by leaving a new shark behind.
-func (w *World) updateShark(x, y int, c *Creature, newGrid [][]*Creature) {
+func (w *World) updateShark(x, y int, c *Creature, newGrid [][]*Creature, rng *rand.Rand) {
 	// Starvation: shark loses 1 energy every chronon.
 	energy := c.Energy - 1
 	if energy <= 0 {
@@ -407,13 +498,13 @@ func (w *World) updateShark(x, y int, c *Creature, newGrid [][]*Creature) {
 
 	if len(fishN) > 0 {
 		// Prefer eating a fish.
-		dest := fishN[rand.Intn(len(fishN))]
+		dest := fishN[rng.Intn(len(fishN))]
 		destX, destY = dest[0], dest[1]
 		ate = true
 	} else {
 		empties := w.emptyNeighbours(x, y)
 		if len(empties) > 0 {
-			dest := empties[rand.Intn(len(empties))]
+			dest := empties[rng.Intn(len(empties))]
 			destX, destY = dest[0], dest[1]
 		} else {
 			// No movement possible: stay where you are.
@@ -424,6 +515,7 @@ func (w *World) updateShark(x, y int, c *Creature, newGrid [][]*Creature) {
 	// If we ate a fish, reset energy.
 	if ate {
 		energy = w.Params.Starve
+		w.predation.add(destX, destY)
 	}
 
 	// If moving into an originally empty cell, but newGrid is already occupied,
@@ -450,5 +542,6 @@ func (w *World) updateShark(x, y int, c *Creature, newGrid [][]*Creature) {
 		Kind:         SharkCell,
 		BreedCounter: breed,
 		Energy:       energy,
+		Age:          c.Age + 1,
 	}
 }